@@ -0,0 +1,133 @@
+// Autopilot: flying a ship through a queue of waypoints.
+
+package lib
+
+import (
+	"math"
+)
+
+// Somewhere to fly to: a ship, a body or, when both are nil, a point.
+type Waypoint struct {
+	Position Vector
+	Body *Body
+	Ship *Ship
+	// Distance to keep from a ship or body's hull.
+	Standoff float64
+}
+
+// Return where s, coming from the given point, should stop for w and how
+// that point is moving. Ships and bodies are approached from that side,
+// Standoff clear of their hulls.
+func (w *Waypoint) target(s *Ship, from Vector) (p, v, a Vector) {
+	var r float64
+	switch {
+	case w.Ship != nil:
+		p, v, a, r = w.Ship.Position, w.Ship.Velocity, w.Ship.Acceleration,
+			w.Ship.Radius
+	case w.Body != nil:
+		p, r = w.Body.Position, w.Body.Radius
+	default:
+		return w.Position, Vector{}, Vector{}
+	}
+	away := from.Minus(&p)
+	if away.IsZero() { away.X = 1 }
+	p.AddWithScaleInPlace(away.Unit(), r + s.Radius + w.Standoff)
+	return
+}
+
+// Flies Ship to each of its Waypoints in turn, coming to rest relative to
+// each before going on, then keeps station on the last. Each leg is flown
+// flip-and-burn: full thrust toward the waypoint, then turn and brake.
+type Autopilot struct {
+	Ship *Ship
+	Waypoints []Waypoint
+	// Acceleration to fly at; zero, or more than the ship can manage, for
+	// its full thrust, which must then be limited.
+	Acceleration float64
+	// How close, and at what relative speed, counts as arrived.
+	Tolerance, SpeedTolerance float64
+	// Waypoints reached so far.
+	Leg int
+}
+
+func (p *Autopilot) Done() bool { return p.Leg >= len(p.Waypoints) }
+
+// Return the acceleration to fly at.
+func (p *Autopilot) thrust() float64 {
+	t := p.Ship.Thrust()
+	if p.Acceleration > 0 && p.Acceleration < t { return p.Acceleration }
+	return t
+}
+
+func (p *Autopilot) Rebind(clones map[*Ship]*Ship) Controller {
+	n := *p
+	if c, ok := clones[p.Ship]; ok { n.Ship = c }
+	n.Waypoints = append([]Waypoint{}, p.Waypoints...)
+	for i := range n.Waypoints {
+		if c, ok := clones[n.Waypoints[i].Ship]; ok { n.Waypoints[i].Ship = c }
+	}
+	return &n
+}
+
+func (p *Autopilot) Redirect() {
+	s := p.Ship
+	if len(p.Waypoints) == 0 {
+		s.Acceleration = Vector{}
+		return
+	}
+	if !p.Done() {
+		at, v, _ := p.Waypoints[p.Leg].target(s, s.Position)
+		if s.Position.Distance(&at) <= p.Tolerance &&
+			s.Velocity.Distance(&v) <= p.SpeedTolerance {
+			p.Leg++
+		}
+	}
+	w := &p.Waypoints[len(p.Waypoints) - 1]
+	if !p.Done() { w = &p.Waypoints[p.Leg] }
+	at, v, a := w.target(s, s.Position)
+	s.Arrive(&at, &v, p.thrust())
+	s.Acceleration.PlusInPlace(&a)
+}
+
+// Return the distance left to fly, leg by leg through the waypoints to go.
+func (p *Autopilot) Remaining() float64 {
+	d, from := 0.0, p.Ship.Position
+	for i := p.Leg; i < len(p.Waypoints); i++ {
+		at, _, _ := p.Waypoints[i].target(p.Ship, from)
+		d += from.Distance(&at)
+		from = at
+	}
+	return d
+}
+
+// Return the time to burn and brake over distance d at acceleration a,
+// already closing at speed w (negative when opening). Arrive brakes a
+// little early, so actual legs take a little longer. With unlimited
+// acceleration there is no burn to speak of; with none, such as when
+// disabled, the ship never gets there.
+func burnTime(d, w, a float64) float64 {
+	if a <= 0 { return math.Inf(1) }
+	if math.IsInf(a, 1) { return 0 }
+	if w > 0 && w*w / (2*a) > d {
+		// Too fast to stop in time: brake, then come back.
+		return w / a + 2 * math.Sqrt((w*w / (2*a) - d) / a)
+	}
+	peak := math.Sqrt(a*d + w*w/2)
+	return (peak - w) / a + peak / a
+}
+
+// Return the estimated seconds until the last waypoint is reached, taking
+// the waypoints as they are now and each later leg from rest.
+func (p *Autopilot) ETA() float64 {
+	s, a := p.Ship, p.thrust()
+	eta, from := 0.0, s.Position
+	for i := p.Leg; i < len(p.Waypoints); i++ {
+		at, v, _ := p.Waypoints[i].target(s, from)
+		toward := at.Minus(&from)
+		d, w := toward.Length(), 0.0
+		if i == p.Leg && d > 0 { w = s.Velocity.Minus(&v).Dot(toward) / d }
+		eta += burnTime(d, w, a)
+		from = at
+	}
+	return eta
+}
@@ -0,0 +1,89 @@
+package lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAutopilot(t *testing.T) {
+	s := &Ship{MaxAcceleration:2}
+	rock := &Body{Position:Vector{Y:100}, Radius:10}
+	p := &Autopilot{Ship:s, Tolerance:0.5, SpeedTolerance:0.1, Waypoints:[]Waypoint{
+		{Position:Vector{X:100}}, {Body:rock, Standoff:5}}}
+	if eta := p.ETA(); !fequal(eta, 2 * math.Sqrt(50) + 2 * math.Sqrt((100 * math.Sqrt2 - 15) / 2)) {
+		t.Errorf("Unexpected ETA %v", eta)
+	}
+	if d := p.Remaining(); !fequal(d, 100 + 100 * math.Sqrt2 - 15) {
+		t.Errorf("Unexpected distance remaining %v", d)
+	}
+	s.SetController(p)
+	w := &World{Bodies:[]*Body{rock}}
+	w.Add(s)
+	for p.Leg == 0 && w.Time < 100 { w.Step(0.1) }
+	if w.Time > 2 * math.Sqrt(50) + 5 {
+		t.Errorf("Expected to reach the first waypoint in about 14s, took %v", w.Time)
+	}
+	for !p.Done() && w.Time < 100 {
+		for _, e := range w.Step(0.1) {
+			if e.Kind == Collision { t.Fatal("Flew into the rock.") }
+		}
+	}
+	for i := 0; i < 100; i++ { w.Step(0.1) }
+	at := Vector{15 / math.Sqrt2, 100 - 15 / math.Sqrt2, 0}
+	if !p.Done() || s.Position.Distance(&at) > 0.5 || s.Velocity.Length() > 0.1 {
+		t.Errorf("Expected to stop off the rock, got %v moving %v", s.Position,
+			s.Velocity)
+	}
+	if p.Remaining() != 0 || p.ETA() != 0 { t.Error("Expected nothing left to fly.") }
+}
+
+func TestAutopilotRendezvous(t *testing.T) {
+	s := &Ship{Radius:1, MaxAcceleration:2}
+	o := &Ship{Position:Vector{X:200}, Velocity:Vector{Y:3}, Radius:1}
+	p := &Autopilot{Ship:s, Acceleration:1, Tolerance:0.5, SpeedTolerance:0.1,
+		Waypoints:[]Waypoint{{Ship:o, Standoff:3}}}
+	s.SetController(p)
+	w := &World{}
+	w.Add(s)
+	w.Add(o)
+	for !p.Done() && w.Time < 200 { w.Step(0.1) }
+	if !p.Done() || s.Distance(o) > 5.5 || s.Velocity.Distance(&o.Velocity) > 0.1 {
+		t.Errorf("Expected to match velocity alongside, got %v moving %v",
+			s.Distance(o), s.Velocity)
+	}
+	// Then keep station.
+	for i := 0; i < 200; i++ { w.Step(0.1) }
+	if math.Abs(s.Distance(o) - 5) > 0.5 { t.Errorf("Drifted to %v", s.Distance(o)) }
+}
+
+func TestPredictAutopilot(t *testing.T) {
+	s := &Ship{MaxAcceleration:2}
+	p := &Autopilot{Ship:s, Tolerance:0.5, SpeedTolerance:0.1,
+		Waypoints:[]Waypoint{{Position:Vector{X:100}}}}
+	s.SetController(p)
+	w := &World{}
+	w.Add(s)
+	path := w.Predict(nil, 30, 0.1, 30).Paths[s]
+	if end := path[len(path) - 1]; end.Distance(&p.Waypoints[0].Position) > 0.5 {
+		t.Errorf("Expected the prediction to fly the autopilot, got %v", end)
+	}
+	if p.Leg != 0 || !s.Position.IsZero() { t.Error("Prediction moved the live ship.") }
+	// Unlimited thrust leaves nothing to burn.
+	s.MaxAcceleration = 0
+	if eta := p.ETA(); eta != 0 { t.Errorf("Expected no burn time, got %v", eta) }
+	// A ship with its engines shot out never arrives.
+	s.Systems[Engines] = 1
+	if eta := p.ETA(); !math.IsInf(eta, 1) { t.Errorf("Expected no arrival, got %v", eta) }
+}
+
+func TestBurnTime(t *testing.T) {
+	if b := burnTime(100, 0, 2); !fequal(b, 2 * math.Sqrt(50)) {
+		t.Errorf("Expected to burn and brake in %v, got %v", 2 * math.Sqrt(50), b)
+	}
+	// Closing at 20 m/s with 100 m to go is just fast enough to stop.
+	if b := burnTime(100, 20, 2); !fequal(b, 10) { t.Errorf("Expected 10s, got %v", b) }
+	if b := burnTime(100, 30, 2); b <= 15 { t.Errorf("Expected to overshoot, got %v", b) }
+	if b := burnTime(100, 0, 0); !math.IsInf(b, 1) {
+		t.Errorf("Expected never to arrive without thrust, got %v", b)
+	}
+}
@@ -0,0 +1,55 @@
+// Obstacle avoidance: steering clear of planets, stations and other ships.
+
+package lib
+
+import (
+	"math"
+)
+
+// A behavior that looks Lookahead seconds along the ship's path, coasting,
+// for the first body, or ship its sensors pick up, it would pass within
+// Margin of, and pushes sideways just hard enough to clear it. It proposes nothing when the way
+// is clear, so it belongs at the head of a Priority.
+type Avoid struct {
+	World *World
+	Lookahead float64
+	Margin float64
+}
+
+// Return the acceleration that takes s clear of a sphere of radius r with
+// relative position p and velocity v, and whether it needs to.
+func (a *Avoid) clear(s *Ship, p, v *Vector, r float64) (Vector, float64, bool) {
+	clearance := s.Radius + r + a.Margin
+	if gap := clearance - p.Length(); gap > 0 {
+		// Already too close: get out the shortest way, within a second.
+		return *p.ScaleTo(math.Min(2 * gap, s.Thrust())), 0, true
+	}
+	t, d := closestApproach(p, v, &Vector{})
+	if t <= 0 || t > a.Lookahead || d >= clearance { return Vector{}, 0, false }
+	miss := *p
+	miss.AddWithScaleInPlace(v, t)
+	if miss.IsZero() {
+		// Head on: any way out will do.
+		if miss = *v.Cross(&Vector{Z: 1}); miss.IsZero() { miss = *v.Cross(&Vector{X: 1}) }
+	}
+	need := 2 * (clearance - d) / (t * t)
+	return *miss.ScaleTo(math.Min(need, s.Thrust())), t, true
+}
+
+func (a *Avoid) Steer(s *Ship) Vector {
+	var steer Vector
+	first := math.Inf(1)
+	try := func(p, v *Vector, r float64) {
+		if push, t, ok := a.clear(s, p, v, r); ok && t < first {
+			steer, first = push, t
+		}
+	}
+	for _, b := range a.World.Bodies {
+		try(s.Position.Minus(&b.Position), &s.Velocity, b.Radius)
+	}
+	for _, c := range a.World.Scan(s) {
+		try(s.Position.Minus(&c.Position), s.Velocity.Minus(&c.Velocity),
+			c.Ship.Radius)
+	}
+	return steer
+}
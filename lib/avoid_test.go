@@ -0,0 +1,52 @@
+package lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAvoidBody(t *testing.T) {
+	w := &World{Bodies:[]*Body{{Name:"Rock", Position:Vector{X:100}, Radius:10}}}
+	s := &Ship{Velocity:Vector{X:10, Y:0.1}, Radius:1, MaxAcceleration:5}
+	w.Add(s)
+	avoid := &Avoid{World:w, Lookahead:20, Margin:2}
+	a := avoid.Steer(s)
+	if a.Y <= 0 || math.Abs(a.Dot(&s.Velocity)) > 1e-9 || a.Length() > 5 {
+		t.Errorf("Expected a sideways push off the near side, got %v", a)
+	}
+	// Steered with nothing else in mind, the ship clears the rock.
+	s.SetController(&SteeringController{s, Priority{avoid,
+		Maneuver(func(s *Ship) { s.Acceleration = Vector{} })}})
+	for i := 0; i < 200; i++ {
+		for _, e := range w.Step(0.1) {
+			if e.Kind == Collision { t.Fatalf("Hit the rock at %v", e.Time) }
+		}
+	}
+	if s.Position.X < 150 { t.Errorf("Expected to fly past, got %v", s.Position) }
+	// Too far ahead to worry about yet.
+	s.Position, s.Velocity = Vector{X:-1000}, Vector{X:10}
+	if a := avoid.Steer(s); !a.IsZero() {
+		t.Errorf("Expected no avoidance beyond the lookahead, got %v", a)
+	}
+}
+
+func TestAvoidShip(t *testing.T) {
+	w := &World{}
+	s := &Ship{Velocity:Vector{X:10}, Radius:1, MaxAcceleration:5, Range:200}
+	o := &Ship{Position:Vector{X:100}, Velocity:Vector{X:-10}, Radius:1}
+	w.Add(s)
+	w.Add(o)
+	a := (&Avoid{World:w, Lookahead:20}).Steer(s)
+	if a.X != 0 || !fequal(a.Length(), 2 * 2 / 25.0) {
+		t.Errorf("Expected a gentle sidestep of a head-on ship, got %v", a)
+	}
+	s.Range = 0
+	if a := (&Avoid{World:w, Lookahead:20}).Steer(s); !a.IsZero() {
+		t.Errorf("Expected no avoidance of an unseen ship, got %v", a)
+	}
+	s.Range = 200
+	o.Position = Vector{X:1}
+	if a := (&Avoid{World:w, Lookahead:20}).Steer(s); a != (Vector{X:-2}) {
+		t.Errorf("Expected to back off an overlapping ship, got %v", a)
+	}
+}
@@ -0,0 +1,195 @@
+// Behavior trees: composable decision making for ship AI.
+
+package lib
+
+type Status int
+
+const (
+	Success Status = iota
+	Failure
+	Running
+)
+
+// What a tree acts on: a ship, the world it flies in and its current
+// target, if any.
+type Agent struct {
+	Ship *Ship
+	World *World
+	Target *Ship
+}
+
+// A node of a behavior tree, ticked once per World step.
+type Node interface {
+	Tick(a *Agent) Status
+}
+
+// Tick children in order until one does not succeed, returning its status;
+// succeed if they all do.
+type Sequence []Node
+
+func (n Sequence) Tick(a *Agent) Status {
+	for _, c := range n {
+		if s := c.Tick(a); s != Success { return s }
+	}
+	return Success
+}
+
+// Tick children in order until one does not fail, returning its status;
+// fail if they all do.
+type Selector []Node
+
+func (n Selector) Tick(a *Agent) Status {
+	for _, c := range n {
+		if s := c.Tick(a); s != Failure { return s }
+	}
+	return Failure
+}
+
+// A decorator swapping its child's success and failure.
+type Invert struct{ Node Node }
+
+func (n Invert) Tick(a *Agent) Status {
+	switch s := n.Node.Tick(a); s {
+	case Success: return Failure
+	case Failure: return Success
+	default: return s
+	}
+}
+
+// A decorator that ticks its child but always succeeds, for optional steps.
+type Optional struct{ Node Node }
+
+func (n Optional) Tick(a *Agent) Status {
+	n.Node.Tick(a)
+	return Success
+}
+
+// A decorator that ticks its child at most once every Period seconds of
+// world time, failing in between.
+type Cooldown struct {
+	Node Node
+	Period float64
+	last float64
+	ticked bool
+}
+
+func (n *Cooldown) Tick(a *Agent) Status {
+	if n.ticked && a.World.Time - n.last < n.Period { return Failure }
+	n.last, n.ticked = a.World.Time, true
+	return n.Node.Tick(a)
+}
+
+// A leaf succeeding when a test of the agent passes, failing otherwise.
+type Condition func(a *Agent) bool
+
+func (c Condition) Tick(a *Agent) Status {
+	if c(a) { return Success }
+	return Failure
+}
+
+// A leaf doing something to the agent.
+type Action func(a *Agent) Status
+
+func (f Action) Tick(a *Agent) Status { return f(a) }
+
+func HasTarget(a *Agent) bool {
+	return a.Target != nil && !a.Target.Destroyed()
+}
+
+// Succeed when the target is within d.
+func InRange(d float64) Condition {
+	return func(a *Agent) bool { return HasTarget(a) && a.Ship.Distance(a.Target) <= d }
+}
+
+func FuelBelow(x float64) Condition {
+	return func(a *Agent) bool { return a.Ship.Fuel < x }
+}
+
+// Succeed when less than the fraction f of the hull is left.
+func HullBelow(f float64) Condition {
+	return func(a *Agent) bool {
+		return a.Ship.Hull > 0 && a.Ship.HullPoints() < f * a.Ship.Hull
+	}
+}
+
+// Succeed when a ship the agent detects will pass within d in the next t
+// seconds, making it the target.
+func Threatened(d, t float64) Condition {
+	return func(a *Agent) bool {
+		var seen []*Ship
+		ships := make(map[*Ship]*Ship)
+		for _, c := range a.World.Scan(a.Ship) {
+			e := c.Estimate()
+			seen, ships[e] = append(seen, e), c.Ship
+		}
+		for _, th := range a.Ship.Threats(seen, false) {
+			if th.Distance > d { break }
+			if th.Time <= t {
+				a.Target = ships[th.Ship]
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Target the nearest ship the agent can detect of another faction, failing
+// if there is none.
+func AcquireNearest(a *Agent) Status {
+	var nearest *Ship
+	for _, c := range a.World.Scan(a.Ship) {
+		if c.Ship.Faction == a.Ship.Faction && a.Ship.Faction != nil { continue }
+		if nearest == nil || a.Ship.Distance(c.Ship) < a.Ship.Distance(nearest) {
+			nearest = c.Ship
+		}
+	}
+	if a.Target = nearest; nearest == nil { return Failure }
+	return Success
+}
+
+// Steer the ship with a behavior for this tick. Steering never finishes,
+// so it is always Running.
+func Steer(b Behavior) Action {
+	return func(a *Agent) Status {
+		a.Ship.Acceleration = truncate(b.Steer(a.Ship), a.Ship.Thrust())
+		return Running
+	}
+}
+
+// Steer the ship with one of its maneuvers against the target, e.g.
+// Engage(func(s, t *Ship) { s.Corkscrew(t, 40) }); fails without one.
+func Engage(maneuver func(s, t *Ship)) Action {
+	return func(a *Agent) Status {
+		if !HasTarget(a) { return Failure }
+		return Steer(Maneuver(func(s *Ship) { maneuver(s, a.Target) }))(a)
+	}
+}
+
+// Fire every ready weapon at the target, succeeding if any fired.
+func Fire(a *Agent) Status {
+	if !HasTarget(a) { return Failure }
+	fired := false
+	for _, w := range a.Ship.Weapons {
+		if a.World.Fire(a.Ship, w, a.Target) { fired = true }
+	}
+	if fired { return Success }
+	return Failure
+}
+
+// Drive a ship with a behavior tree, ticked each World step. The ship
+// coasts unless a leaf steers it.
+type BehaviorTree struct {
+	Agent *Agent
+	Root Node
+	// The status the root returned on the last tick.
+	Status Status
+}
+
+func NewBehaviorTree(s *Ship, w *World, root Node) *BehaviorTree {
+	return &BehaviorTree{Agent: &Agent{Ship: s, World: w}, Root: root}
+}
+
+func (t *BehaviorTree) Redirect() {
+	t.Agent.Ship.Acceleration = Vector{}
+	t.Status = t.Root.Tick(t.Agent)
+}
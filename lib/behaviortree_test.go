@@ -0,0 +1,89 @@
+package lib
+
+import (
+	"testing"
+)
+
+// A leaf returning a fixed status and counting its ticks.
+type stub struct {
+	status Status
+	ticks int
+}
+
+func (n *stub) Tick(a *Agent) Status {
+	n.ticks++
+	return n.status
+}
+
+func TestComposites(t *testing.T) {
+	a := &Agent{World:&World{}}
+	ok, fail, run := &stub{status:Success}, &stub{status:Failure}, &stub{status:Running}
+	if s := (Sequence{ok, run, fail}).Tick(a); s != Running || fail.ticks != 0 {
+		t.Errorf("Expected the sequence to stop running, got %v", s)
+	}
+	if s := (Selector{fail, ok, run}).Tick(a); s != Success || run.ticks != 1 {
+		t.Errorf("Expected the selector to stop at success, got %v", s)
+	}
+	if s := (Selector{fail, Invert{ok}}).Tick(a); s != Failure {
+		t.Errorf("Expected every option to fail, got %v", s)
+	}
+	if s := (Sequence{Optional{fail}, Invert{fail}}).Tick(a); s != Success {
+		t.Errorf("Expected optional failures to be skipped, got %v", s)
+	}
+	c := &Cooldown{Node:ok, Period:2}
+	ok.ticks = 0
+	for i := 0; i < 5; i++ {
+		c.Tick(a)
+		a.World.Time++
+	}
+	if ok.ticks != 3 { t.Errorf("Expected 3 ticks through the cooldown, got %v", ok.ticks) }
+}
+
+func TestConditions(t *testing.T) {
+	s := &Ship{Hull:10, HullDamage:6, Fuel:5}
+	o := &Ship{Position:Vector{X:10}, Velocity:Vector{X:-1}}
+	w := &World{}
+	w.Add(s)
+	w.Add(o)
+	a := &Agent{Ship:s, World:w}
+	if InRange(20)(a) || !HullBelow(0.5)(a) || !FuelBelow(6)(a) || FuelBelow(5)(a) {
+		t.Error("Unexpected conditions without a target.")
+	}
+	if Threatened(1, 10)(a) { t.Error("Threatened by an unseen ship.") }
+	s.Range = 20
+	if Threatened(1, 5)(a) || !Threatened(1, 10)(a) || a.Target != o {
+		t.Error("Expected the other ship to threaten within 10s.")
+	}
+	if !InRange(10)(a) || InRange(9)(a) { t.Error("Expected the target at 10.") }
+}
+
+func TestBehaviorTree(t *testing.T) {
+	w := &World{}
+	fighter := &Ship{Range:100, Hull:10, MaxAcceleration:1,
+		Weapons:[]*Weapon{{Kind:Beam, Range:20, Damage:1, RateOfFire:1}}}
+	enemy := &Ship{Position:Vector{X:50}, Hull:100}
+	w.Add(fighter)
+	w.Add(enemy)
+	tree := NewBehaviorTree(fighter, w, Selector{
+		Sequence{Condition(HullBelow(0.5)), Engage(func(s, t *Ship) {
+			s.Flee(&t.Position, 1) })},
+		Sequence{Action(AcquireNearest), Optional{Sequence{InRange(20), Action(Fire)}},
+			Engage(func(s, t *Ship) { s.Approach(t, 1) })}})
+	fighter.SetController(tree)
+	w.Step(1)
+	if tree.Status != Running || tree.Agent.Target != enemy || fighter.Velocity.X <= 0 {
+		t.Errorf("Expected to close on the enemy, got %v", fighter.Velocity)
+	}
+	for i := 0; i < 20 && enemy.HullDamage == 0; i++ { w.Step(1) }
+	if enemy.HullDamage == 0 { t.Error("Expected to open fire in range.") }
+	fighter.HullDamage = 6
+	w.Step(1)
+	if fighter.Acceleration.X >= 0 {
+		t.Errorf("Expected to break off when damaged, got %v", fighter.Acceleration)
+	}
+	w.Remove(enemy)
+	fighter.HullDamage = 0
+	if w.Step(1); tree.Status != Failure || !fighter.Acceleration.IsZero() {
+		t.Errorf("Expected to coast with nothing to do, got %v", tree.Status)
+	}
+}
@@ -0,0 +1,111 @@
+// Boarding actions: taking a disabled ship's cargo, credits or the ship.
+
+package lib
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrNotDisabled = errors.New("target is not disabled")
+	ErrTooFar = errors.New("target is too far away to board")
+	ErrNotMatched = errors.New("velocity is not matched with target")
+	ErrNoCrew = errors.New("ship has no crew to board with")
+)
+
+const (
+	// Most distance from hull to hull, and relative speed, to board at.
+	boardingRange = 10.0
+	boardingSpeed = 1.0
+	// Chance each crew member has to put an enemy out of the fight each
+	// round, and the defenders' edge for fighting on their own deck.
+	lethality = 0.1
+	defenderAdvantage = 1.5
+)
+
+// What boarders take if they win.
+type Prize int
+
+const (
+	SeizeCargo Prize = iota
+	SeizeCredits
+	// The ship itself, with everything aboard.
+	SeizeShip
+)
+
+// The outcome of a boarding action.
+type Boarding struct {
+	Won bool
+	AttackerLosses, DefenderLosses int
+	// What was carried off.
+	Cargo map[*Commodity]float64
+	Credits float64
+}
+
+// Check that s can board t: t must be disabled and s alongside it with
+// velocity matched.
+func (s *Ship) CanBoard(t *Ship) error {
+	if s.Disabled() { return ErrDisabled }
+	if s.Crew <= 0 { return ErrNoCrew }
+	if !t.Disabled() { return ErrNotDisabled }
+	if s.Distance(t) - s.Radius - t.Radius > boardingRange { return ErrTooFar }
+	if s.Velocity.Distance(&t.Velocity) > boardingSpeed { return ErrNotMatched }
+	return nil
+}
+
+// Return how many of an enemy n fighters put down in a round, each with
+// chance p; with no source of randomness, the expected number rounded up.
+func (w *World) casualties(n int, p float64) int {
+	if w.Rand == nil { return int(math.Ceil(float64(n) * p)) }
+	k := 0
+	for i := 0; i < n; i++ {
+		if w.Rand.Float64() < p { k++ }
+	}
+	return k
+}
+
+// Send s's crew to board t, fighting t's crew until one side is gone or the
+// boarders have lost half their number and fall back. If they win, they
+// take the prize.
+func (w *World) Board(s, t *Ship, prize Prize) (*Boarding, error) {
+	if err := s.CanBoard(t); err != nil { return nil, err }
+	b := &Boarding{Cargo: make(map[*Commodity]float64)}
+	attackers, defenders := s.Crew, t.Crew
+	for defenders > 0 && 2 * attackers > s.Crew {
+		a := w.casualties(defenders, lethality * defenderAdvantage)
+		d := w.casualties(attackers, lethality)
+		if attackers -= a; attackers < 0 { attackers = 0 }
+		if defenders -= d; defenders < 0 { defenders = 0 }
+	}
+	b.AttackerLosses, b.DefenderLosses = s.Crew - attackers, t.Crew - defenders
+	s.Crew, t.Crew = attackers, defenders
+	w.pending = append(w.pending, Event{Kind: Boarded, Time: w.Time, Ship: t,
+		Other: s})
+	if b.Won = defenders == 0; !b.Won { return b, nil }
+	switch prize {
+	case SeizeCargo:
+		for _, c := range Commodities {
+			q := t.Hold.Goods[c]
+			if room := s.Hold.Free() / c.Mass; room < q { q = room }
+			if q <= 0 { continue }
+			t.Hold.add(c, -q)
+			s.Hold.add(c, q)
+			b.Cargo[c] = q
+		}
+	case SeizeCredits:
+		b.Credits, s.Credits, t.Credits = t.Credits, s.Credits + t.Credits, 0
+	case SeizeShip:
+		for c, q := range t.Hold.Goods { b.Cargo[c] = q }
+		b.Credits = t.Credits
+		// Half the boarders stay aboard as a prize crew.
+		t.Crew = (s.Crew + 1) / 2
+		s.Crew -= t.Crew
+		if t.Faction != nil { t.Faction.Decommission(t) }
+		if s.Faction != nil { s.Faction.Commission(t, "Prizes") }
+		t.c = nil
+		w.pending = append(w.pending, Event{Kind: Captured, Time: w.Time,
+			Ship: t, Other: s})
+	}
+	return b, nil
+}
@@ -0,0 +1,86 @@
+package lib
+
+import (
+	"testing"
+)
+
+// Return a pirate alongside a merchant whose engines have been shot out.
+func boardingParty() (w *World, pirate, merchant *Ship) {
+	pirate = &Ship{Radius:5, Crew:20, Hold:Hold{Capacity:10}}
+	merchant = &Ship{Position:Vector{X:15}, Radius:5, Crew:4, Credits:500,
+		Hold:Hold{Capacity:100, Goods:map[*Commodity]float64{Ore:20}}}
+	merchant.Systems[Engines] = 1
+	w = &World{}
+	w.Add(pirate)
+	w.Add(merchant)
+	return
+}
+
+func TestCanBoard(t *testing.T) {
+	w, pirate, merchant := boardingParty()
+	if _, err := w.Board(merchant, pirate, SeizeCargo); err != ErrDisabled {
+		t.Errorf("Expected a disabled ship not to board, got %v", err)
+	}
+	merchant.Systems[Engines] = 0
+	if err := pirate.CanBoard(merchant); err != ErrNotDisabled {
+		t.Errorf("Expected ErrNotDisabled, got %v", err)
+	}
+	merchant.Systems[Engines] = 1
+	merchant.Position.X = 100
+	if err := pirate.CanBoard(merchant); err != ErrTooFar {
+		t.Errorf("Expected ErrTooFar, got %v", err)
+	}
+	merchant.Position.X = 15
+	merchant.Velocity.Y = 2
+	if err := pirate.CanBoard(merchant); err != ErrNotMatched {
+		t.Errorf("Expected ErrNotMatched, got %v", err)
+	}
+}
+
+func TestBoardForCargo(t *testing.T) {
+	w, pirate, merchant := boardingParty()
+	b, err := w.Board(pirate, merchant, SeizeCargo)
+	if err != nil { t.Fatal(err) }
+	if !b.Won || merchant.Crew != 0 || pirate.Crew != 20 - b.AttackerLosses {
+		t.Errorf("Expected the pirates to win, got %v", b)
+	}
+	// Only as much ore as fits in the pirate's hold.
+	if b.Cargo[Ore] != 10 / Ore.Mass || merchant.Hold.Goods[Ore] != 20 - 10 / Ore.Mass {
+		t.Errorf("Expected %v ore taken, got %v", 10 / Ore.Mass, b.Cargo[Ore])
+	}
+	if merchant.Credits != 500 { t.Error("Credits taken with the cargo.") }
+	if events := w.Step(1); len(events) != 1 || events[0].Kind != Boarded ||
+		events[0].Other != pirate {
+		t.Errorf("Expected a boarding event, got %v", events)
+	}
+}
+
+func TestBoardingRepelled(t *testing.T) {
+	w, pirate, merchant := boardingParty()
+	merchant.Crew = 40
+	b, err := w.Board(pirate, merchant, SeizeCredits)
+	if err != nil { t.Fatal(err) }
+	if b.Won || pirate.Crew > 10 || merchant.Credits != 500 {
+		t.Errorf("Expected the boarders to fall back, got %v", b)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	w, pirate, merchant := boardingParty()
+	pirates, guild := NewFaction("Pirates"), NewFaction("Guild")
+	pirates.Commission(pirate, "Raiders")
+	guild.Commission(merchant, "Convoy")
+	merchant.SetController(&ManeuverController{Ship:merchant})
+	b, err := w.Board(pirate, merchant, SeizeShip)
+	if err != nil { t.Fatal(err) }
+	if !b.Won || b.Credits != 500 || b.Cargo[Ore] != 20 {
+		t.Errorf("Expected the ship and all aboard, got %v", b)
+	}
+	if merchant.Faction != pirates || len(guild.Ships()) != 0 ||
+		len(pirates.Ships()) != 2 || merchant.c != nil {
+		t.Error("Expected the merchant to change hands.")
+	}
+	if merchant.Crew + pirate.Crew != 20 - b.AttackerLosses || merchant.Crew == 0 {
+		t.Errorf("Expected a prize crew, got %v", merchant.Crew)
+	}
+}
@@ -0,0 +1,99 @@
+// Cargo holds and trading with markets.
+
+package lib
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidQuantity = errors.New("quantity must be positive")
+	ErrInsufficientFunds = errors.New("not enough credits")
+	ErrInsufficientSpace = errors.New("not enough cargo space")
+	ErrInsufficientStock = errors.New("market does not have enough stock")
+	ErrInsufficientCargo = errors.New("hold does not have enough cargo")
+)
+
+type Hold struct {
+	// Tonnes of cargo the hold can carry.
+	Capacity float64
+	Goods map[*Commodity]float64
+}
+
+// Return the tonnes of cargo aboard.
+func (h *Hold) Mass() float64 {
+	m := 0.0
+	for c, q := range h.Goods { m += c.Mass * q }
+	return m
+}
+
+func (h *Hold) Free() float64 { return h.Capacity - h.Mass() }
+
+func (h *Hold) add(c *Commodity, q float64) {
+	if h.Goods == nil { h.Goods = make(map[*Commodity]float64) }
+	if h.Goods[c] += q; h.Goods[c] <= 0 { delete(h.Goods, c) }
+}
+
+// Return the mass of the ship laden with its cargo.
+func (s *Ship) TotalMass() float64 { return s.Mass + s.Hold.Mass() }
+
+type Transaction struct {
+	Time float64
+	Ship *Ship
+	Commodity *Commodity
+	// Units bought by the ship, negative when sold, and the unit price.
+	Quantity, Price float64
+}
+
+// Return the unit price for trading q units, which is the price at the
+// midpoint of the stock's change. Positive q buys from the market.
+func (m *Market) quote(c *Commodity, q float64) float64 {
+	s := m.Goods[c]
+	saved := s.Quantity
+	s.Quantity -= q / 2
+	price := m.Price(c)
+	s.Quantity = saved
+	return price
+}
+
+func (m *Market) record(s *Ship, c *Commodity, q, price float64) {
+	m.Goods[c].Quantity -= q
+	s.Credits -= q * price
+	s.Hold.add(c, q)
+	m.Log = append(m.Log, Transaction{m.Time, s, c, q, price})
+}
+
+// Buy q units of c from the market into the ship's hold.
+func (m *Market) Buy(s *Ship, c *Commodity, q float64) error {
+	if q <= 0 { return ErrInvalidQuantity }
+	stock := m.Goods[c]
+	if stock == nil || stock.Quantity < q { return ErrInsufficientStock }
+	price := m.quote(c, q)
+	if q * price > s.Credits { return ErrInsufficientFunds }
+	if q * c.Mass > s.Hold.Free() { return ErrInsufficientSpace }
+	m.record(s, c, q, price)
+	return nil
+}
+
+// Pump q units of the market's Fuel straight into the ship's tanks.
+func (m *Market) Refuel(s *Ship, q float64) error {
+	if q <= 0 { return ErrInvalidQuantity }
+	stock := m.Goods[Fuel]
+	if stock == nil || stock.Quantity < q { return ErrInsufficientStock }
+	price := m.quote(Fuel, q)
+	if q * price > s.Credits { return ErrInsufficientFunds }
+	stock.Quantity -= q
+	s.Credits -= q * price
+	s.Fuel += q
+	m.Log = append(m.Log, Transaction{m.Time, s, Fuel, q, price})
+	return nil
+}
+
+// Sell q units of c from the ship's hold to the market.
+func (m *Market) Sell(s *Ship, c *Commodity, q float64) error {
+	if q <= 0 { return ErrInvalidQuantity }
+	if s.Hold.Goods[c] < q { return ErrInsufficientCargo }
+	if m.Goods[c] == nil { m.Goods[c] = &Stock{Equilibrium: 100} }
+	m.record(s, c, -q, m.quote(c, -q))
+	return nil
+}
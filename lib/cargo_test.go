@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBuyAndSell(t *testing.T) {
+	m := NewMarket(&Star{Class:"G2V"}, []*Body{{Kind:Ocean}})
+	s := &Ship{Credits:1000, Hold:Hold{Capacity:50}}
+	if err := m.Buy(s, Food, 0); err != ErrInvalidQuantity {
+		t.Errorf("Expected %v, got %v", ErrInvalidQuantity, err)
+	}
+	if err := m.Buy(s, Luxuries, 3); err != ErrInsufficientFunds {
+		t.Errorf("Expected %v, got %v", ErrInsufficientFunds, err)
+	}
+	if err := m.Buy(s, Food, 60); err != ErrInsufficientSpace {
+		t.Errorf("Expected %v, got %v", ErrInsufficientSpace, err)
+	}
+	if err := m.Buy(s, Food, 1e6); err != ErrInsufficientStock {
+		t.Errorf("Expected %v, got %v", ErrInsufficientStock, err)
+	}
+	price := m.Price(Food)
+	if err := m.Buy(s, Food, 40); err != nil {
+		t.Fatalf("Purchase failed: %v", err)
+	}
+	paid := 1000 - s.Credits
+	if s.Hold.Goods[Food] != 40 || paid <= 40 * price || paid > 41 * price {
+		t.Errorf("Expected to pay a little over %v for 40 food, paid %v",
+			40 * price, paid)
+	}
+	if err := m.Sell(s, Food, 41); err != ErrInsufficientCargo {
+		t.Errorf("Expected %v, got %v", ErrInsufficientCargo, err)
+	}
+	if err := m.Sell(s, Food, 40); err != nil {
+		t.Fatalf("Sale failed: %v", err)
+	}
+	if len(s.Hold.Goods) != 0 || !fequal(s.Credits, 1000) {
+		t.Errorf("Round trip should be free; hold %v, credits %v",
+			s.Hold.Goods, s.Credits)
+	}
+	if len(m.Log) != 2 || m.Log[0].Quantity != 40 || m.Log[1].Quantity != -40 {
+		t.Errorf("Unexpected transaction log %v", m.Log)
+	}
+}
+
+func TestRefuel(t *testing.T) {
+	m := NewMarket(&Star{Class:"G2V"}, []*Body{{Kind:GasGiant}})
+	s := &Ship{Credits:1000}
+	stock := m.Goods[Fuel].Quantity
+	if err := m.Refuel(s, stock + 1); err != ErrInsufficientStock {
+		t.Errorf("Expected %v, got %v", ErrInsufficientStock, err)
+	}
+	if err := m.Refuel(s, 10); err != nil { t.Fatalf("Refuelling failed: %v", err) }
+	if s.Fuel != 10 || len(s.Hold.Goods) != 0 || m.Goods[Fuel].Quantity != stock - 10 {
+		t.Errorf("Expected 10 fuel pumped from stock, got %v", s.Fuel)
+	}
+	if len(m.Log) != 1 || m.Log[0].Commodity != Fuel ||
+		math.Abs(1000 - s.Credits - 10 * m.Log[0].Price) > 1e-9 {
+		t.Errorf("Unexpected transaction log %v", m.Log)
+	}
+}
+
+func TestCargoSlowsShip(t *testing.T) {
+	s := &Ship{MaxAcceleration:10, Mass:100, Hold:Hold{Capacity:100}}
+	if a := s.Thrust(); a != 10 {
+		t.Errorf("Empty ship should accelerate at 10, got %v", a)
+	}
+	s.Hold.add(Ore, 50)
+	if s.TotalMass() != 200 {
+		t.Errorf("Expected total mass 200, got %v", s.TotalMass())
+	}
+	if a := s.Thrust(); a != 5 {
+		t.Errorf("Laden ship should accelerate at 5, got %v", a)
+	}
+}
@@ -0,0 +1,221 @@
+// Broadphase and swept-sphere detection of collisions and range crossings.
+
+package lib
+
+import (
+	"math"
+	"sort"
+)
+
+type EventKind int
+
+const (
+	Collision EventKind = iota
+	EnterRange
+	LeaveRange
+	Hit
+	Detonation
+	Damaged
+	Disabled
+	Destroyed
+	Departed
+	Arrived
+	Boarded
+	Captured
+)
+
+// Something that happened to Ship during a World step. Other is set for
+// events between two ships, Body for events between a ship and a body.
+// Range events are reported from the point of view of the ship whose Range
+// was crossed, Hit, Detonation and damage events name the shooter as Other,
+// and boarding events the boarder.
+type Event struct {
+	Kind EventKind
+	Time float64
+	Ship, Other *Ship
+	Body *Body
+	Damage float64
+	// Where a Hit or Detonation struck, relative to the ship's centre.
+	Impact Vector
+}
+
+type byTime []Event
+
+func (e byTime) Len() int { return len(e) }
+func (e byTime) Less(i, j int) bool { return e[i].Time < e[j].Time }
+func (e byTime) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+// An object as seen by the detector: where it starts and how far it goes.
+type proxy struct {
+	p, d Vector
+	r, reach float64
+	ship *Ship
+	body *Body
+	shot *Projectile
+}
+
+// Where in the step a projectile first strikes something.
+type impact struct {
+	s float64
+	ship *Ship
+	// Where the shot struck, relative to the ship.
+	at Vector
+}
+
+// The displacement Move(t) will apply to the ship.
+func (s *Ship) displacement(t float64) Vector {
+	d := s.Velocity.Times(t)
+	d.AddWithScaleInPlace(&s.Acceleration, 0.5*t*t)
+	return *d
+}
+
+// Return the fraction of the step at which two spheres starting p apart
+// and closing by d first touch, or -1 if they stay apart (or start touching).
+func sweep(p, d *Vector, r float64) float64 {
+	a, b, c := d.SquaredLength(), p.Dot(d), p.SquaredLength() - r*r
+	if c <= 0 || a == 0 || b >= 0 { return -1 }
+	disc := b*b - a*c
+	if disc < 0 { return -1 }
+	if s := (-b - math.Sqrt(disc)) / a; s <= 1 { return s }
+	return -1
+}
+
+type cell [3]int
+
+type byIndex [][2]int
+
+func (p byIndex) Len() int { return len(p) }
+func (p byIndex) Less(i, j int) bool {
+	return p[i][0] < p[j][0] || p[i][0] == p[j][0] && p[i][1] < p[j][1]
+}
+func (p byIndex) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+func (w *World) proxies(dt float64) []proxy {
+	var ps []proxy
+	for _, s := range w.Ships {
+		ps = append(ps, proxy{s.Position, s.displacement(dt), s.Radius,
+			s.Radius + s.SensorRange(), s, nil, nil})
+	}
+	for _, b := range w.Bodies {
+		ps = append(ps, proxy{b.Position, Vector{}, b.Radius, b.Radius, nil, b, nil})
+	}
+	for _, p := range w.Projectiles {
+		ps = append(ps, proxy{p.Position, *p.Velocity.Times(dt), 0, 0, nil, nil, p})
+	}
+	return ps
+}
+
+func hullOf(p *proxy) float64 { return p.r }
+
+func reachOf(p *proxy) float64 { return p.reach }
+
+// Bucket the swept bounds of every proxy, padded by pad, into a uniform grid
+// and return the pairs sharing at least one cell. Cells are grown to at
+// least the widest padded bounds, so no proxy spans more than two cells
+// along any axis.
+func broadphase(ps []proxy, size float64, pad func(*proxy) float64) [][2]int {
+	for i := range ps {
+		if e := 2 * (ps[i].d.Length() + pad(&ps[i])); e > size { size = e }
+	}
+	if size <= 0 { size = 1 }
+	grid := make(map[cell][]int)
+	for i := range ps {
+		p, q := ps[i].p, *ps[i].p.Plus(&ps[i].d)
+		lo := Vector{math.Min(p.X, q.X), math.Min(p.Y, q.Y), math.Min(p.Z, q.Z)}
+		hi := Vector{math.Max(p.X, q.X), math.Max(p.Y, q.Y), math.Max(p.Z, q.Z)}
+		r := pad(&ps[i])
+		for x := int(math.Floor((lo.X - r) / size)); x <= int(math.Floor((hi.X + r) / size)); x++ {
+			for y := int(math.Floor((lo.Y - r) / size)); y <= int(math.Floor((hi.Y + r) / size)); y++ {
+				for z := int(math.Floor((lo.Z - r) / size)); z <= int(math.Floor((hi.Z + r) / size)); z++ {
+					k := cell{x, y, z}
+					grid[k] = append(grid[k], i)
+				}
+			}
+		}
+	}
+	seen := make(map[[2]int]bool)
+	var pairs [][2]int
+	for _, members := range grid {
+		for a := 0; a < len(members); a++ {
+			for b := a + 1; b < len(members); b++ {
+				pair := [2]int{members[a], members[b]}
+				if pair[0] > pair[1] { pair[0], pair[1] = pair[1], pair[0] }
+				if !seen[pair] {
+					seen[pair] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+	sort.Sort(byIndex(pairs))
+	return pairs
+}
+
+// Report a crossing of the observer's range by the other proxy.
+func crossing(e []Event, t float64, o, x *proxy, before, after float64) []Event {
+	if o.ship == nil || o.ship.SensorRange() <= 0 { return e }
+	limit := o.r + o.ship.SensorRange() + x.r
+	ev := Event{Time: t, Ship: o.ship, Other: x.ship, Body: x.body}
+	if before > limit && after <= limit {
+		ev.Kind = EnterRange
+		e = append(e, ev)
+	} else if before <= limit && after > limit {
+		ev.Kind = LeaveRange
+		e = append(e, ev)
+	}
+	return e
+}
+
+// Find every collision, range crossing and projectile hit that will happen
+// over the next dt seconds. Nothing is moved, but projectiles that strike
+// something are spent.
+func (w *World) detect(dt float64) []Event {
+	ps := w.proxies(dt)
+	var events []Event
+	impacts := make(map[*Projectile]impact)
+	for _, pair := range broadphase(ps, w.CellSize, hullOf) {
+		a, b := &ps[pair[0]], &ps[pair[1]]
+		if a.shot != nil || b.shot != nil {
+			if a.shot == nil { a, b = b, a }
+			if b.shot != nil || b.ship != nil && b.ship == a.shot.Owner { continue }
+			p, d := b.p.Minus(&a.p), b.d.Minus(&a.d)
+			if s := sweep(p, d, b.r); s >= 0 {
+				if h, ok := impacts[a.shot]; !ok || s < h.s {
+					impacts[a.shot] = impact{s, b.ship, *p.Plus(d.Times(s)).Times(-1)}
+				}
+			}
+			continue
+		}
+		if a.ship == nil && b.ship == nil { continue }
+		// Keep the ship first so that events name it as the subject.
+		if a.ship == nil { a, b = b, a }
+		p, d := a.p.Minus(&b.p), a.d.Minus(&b.d)
+		if s := sweep(p, d, a.r+b.r); s >= 0 {
+			events = append(events, Event{Kind: Collision, Time: w.Time + s*dt,
+				Ship: a.ship, Other: b.ship, Body: b.body})
+		}
+	}
+	// Sensors reach much further than hulls, so range crossings are found on
+	// a coarser grid of their own, keeping the collision cells small.
+	for _, pair := range broadphase(ps, w.CellSize, reachOf) {
+		a, b := &ps[pair[0]], &ps[pair[1]]
+		if a.shot != nil || b.shot != nil || a.ship == nil && b.ship == nil {
+			continue
+		}
+		p, d := a.p.Minus(&b.p), a.d.Minus(&b.d)
+		before, after := p.Length(), p.Plus(d).Length()
+		events = crossing(events, w.Time + dt, a, b, before, after)
+		events = crossing(events, w.Time + dt, b, a, before, after)
+	}
+	for i := range ps {
+		h, ok := impacts[ps[i].shot]
+		if !ok { continue }
+		ps[i].shot.TTL = 0
+		if h.ship != nil {
+			events = append(events, Event{Kind: Hit, Time: w.Time + h.s*dt,
+				Ship: h.ship, Other: ps[i].shot.Owner, Damage: ps[i].shot.Damage,
+				Impact: h.at})
+		}
+	}
+	return events
+}
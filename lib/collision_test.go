@@ -0,0 +1,103 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestCollisionNoTunnelling(t *testing.T) {
+	a := &Ship{Position:Vector{X:-100}, Velocity:Vector{X:1000}, Radius:1}
+	b := &Ship{Position:Vector{X:100}, Velocity:Vector{X:-1000}, Radius:1}
+	w := &World{Ships:[]*Ship{a, b}}
+	events := w.Step(1)
+	if len(events) != 1 || events[0].Kind != Collision {
+		t.Fatalf("Expected a single collision, got %v", events)
+	}
+	if e := events[0]; e.Ship != a || e.Other != b || !fequal(e.Time, 0.099) {
+		t.Errorf("Expected collision of %v and %v at 0.099, got %v", a, b, e)
+	}
+	if events = w.Step(1); len(events) != 0 {
+		t.Errorf("Ships that have passed should not collide again: %v", events)
+	}
+}
+
+func TestCollisionWithBody(t *testing.T) {
+	s := &Ship{Velocity:Vector{Y:10}, Radius:1}
+	p := &Body{Name:"Rock", Position:Vector{Y:50}, Radius:5}
+	w := &World{Ships:[]*Ship{s}, Bodies:[]*Body{p}}
+	for i := 0; i < 3; i++ {
+		if events := w.Step(1); len(events) != 0 {
+			t.Fatalf("Unexpected events %v at %v", events, w.Time)
+		}
+	}
+	events := w.Step(2)
+	if len(events) != 1 || events[0].Body != p || events[0].Ship != s {
+		t.Errorf("Expected collision with %v, got %v", p, events)
+	}
+}
+
+func TestRangeCrossing(t *testing.T) {
+	a := &Ship{Range:10}
+	b := &Ship{Position:Vector{X:20}, Velocity:Vector{X:-5}}
+	w := &World{Ships:[]*Ship{a, b}}
+	var kinds []EventKind
+	for i := 0; i < 3; i++ {
+		for _, e := range w.Step(1) {
+			if e.Ship != a || e.Other != b {
+				t.Errorf("Range event reported for the wrong ships: %v", e)
+			}
+			kinds = append(kinds, e.Kind)
+		}
+	}
+	if len(kinds) != 1 || kinds[0] != EnterRange {
+		t.Errorf("Expected ship to enter range, got %v", kinds)
+	}
+	b.Velocity.X = 5
+	for i := 0; i < 3; i++ {
+		for _, e := range w.Step(1) { kinds = append(kinds, e.Kind) }
+	}
+	if len(kinds) != 2 || kinds[1] != LeaveRange {
+		t.Errorf("Expected ship to leave range, got %v", kinds)
+	}
+}
+
+func TestBroadphaseSkipsDistantShips(t *testing.T) {
+	w := &World{CellSize:10}
+	for i := 0; i < 10; i++ {
+		w.Ships = append(w.Ships, &Ship{Position:Vector{X:float64(100 * i)}})
+	}
+	if pairs := broadphase(w.proxies(1), w.CellSize, hullOf); len(pairs) != 0 {
+		t.Errorf("Expected no candidate pairs, got %v", pairs)
+	}
+}
+
+func TestBroadphaseLongRange(t *testing.T) {
+	// A long sensor range must neither be missed by small cells nor grow
+	// the collision cells.
+	w := &World{CellSize:1}
+	s := &Ship{Range:500}
+	w.Add(s)
+	w.Add(&Ship{Position:Vector{X:505}, Velocity:Vector{X:-10}})
+	w.Add(&Ship{Position:Vector{Y:300}, Radius:1})
+	w.Add(&Ship{Position:Vector{Y:330}, Radius:1})
+	ps := w.proxies(1)
+	if pairs := broadphase(ps, w.CellSize, reachOf); len(pairs) != 6 {
+		t.Errorf("Expected every ship in range of the first, got %v", pairs)
+	}
+	if pairs := broadphase(ps, w.CellSize, hullOf); len(pairs) != 0 {
+		t.Errorf("Expected no collision candidates, got %v", pairs)
+	}
+	if events := w.Step(1); len(events) != 1 || events[0].Kind != EnterRange {
+		t.Errorf("Expected the ship to enter range, got %v", events)
+	}
+}
+
+func BenchmarkWorldStep(b *testing.B) {
+	w := &World{}
+	for i := 0; i < 100; i++ {
+		w.Ships = append(w.Ships, &Ship{Position:Vector{float64(i), 0, 0},
+			Velocity:Vector{0, float64(i), 0}, Radius:0.1, Range:2})
+	}
+	for i := 0; i < b.N; i++ {
+		w.Step(0.01)
+	}
+}
@@ -0,0 +1,106 @@
+// Closest point of approach between ships.
+
+package lib
+
+import (
+	"math"
+	"sort"
+)
+
+// Return the real roots of a*x^3 + b*x^2 + c*x + d, degrading gracefully
+// to lower order polynomials as leading coefficients vanish.
+func cubicRoots(a, b, c, d float64) []float64 {
+	if a == 0 {
+		if b == 0 {
+			if c == 0 { return nil }
+			return []float64{-d / c}
+		}
+		disc := c*c - 4*b*d
+		if disc < 0 { return nil }
+		sq := math.Sqrt(disc)
+		return []float64{(-c - sq) / (2*b), (-c + sq) / (2*b)}
+	}
+	// Depressed cubic t^3 + p*t + q with x = t - b/3a.
+	b, c, d = b/a, c/a, d/a
+	p, q := c - b*b/3, 2*b*b*b/27 - b*c/3 + d
+	shift := -b / 3
+	disc := q*q/4 + p*p*p/27
+	if disc > 0 {
+		sq := math.Sqrt(disc)
+		return []float64{math.Cbrt(-q/2 + sq) + math.Cbrt(-q/2 - sq) + shift}
+	}
+	if p == 0 { return []float64{shift} }
+	// Three real roots; use the trigonometric form.
+	r := 2 * math.Sqrt(-p/3)
+	phi := math.Acos(math.Max(-1, math.Min(1, 3*q/(p*r))))
+	return []float64{
+		r*math.Cos(phi/3) + shift,
+		r*math.Cos((phi - 2*math.Pi)/3) + shift,
+		r*math.Cos((phi - 4*math.Pi)/3) + shift,
+	}
+}
+
+// Given a relative position p, velocity v and acceleration a, return the
+// time from now and the distance of closest approach.
+func closestApproach(p, v, a *Vector) (float64, float64) {
+	at := func(t float64) float64 {
+		r := *p
+		r.AddWithScaleInPlace(v, t)
+		r.AddWithScaleInPlace(a, 0.5*t*t)
+		return r.Length()
+	}
+	// Roots of d/dt |r(t)|^2 / 2 = r(t) . r'(t).
+	roots := cubicRoots(0.5*a.Dot(a), 1.5*v.Dot(a), v.Dot(v) + p.Dot(a), p.Dot(v))
+	best, bestD := 0.0, p.Length()
+	for _, t := range roots {
+		if t <= 0 || math.IsNaN(t) { continue }
+		if d := at(t); d < bestD { best, bestD = t, d }
+	}
+	return best, bestD
+}
+
+// Return how many seconds from now s and t will be closest, and how far
+// apart they will be then, if both hold their current velocity.
+func (s *Ship) ClosestApproach(t *Ship) (time, distance float64) {
+	return closestApproach(s.Position.Minus(&t.Position),
+		s.Velocity.Minus(&t.Velocity), &Vector{})
+}
+
+// As ClosestApproach, but with both ships also holding their current
+// acceleration.
+func (s *Ship) ClosestApproachAccelerating(t *Ship) (time, distance float64) {
+	return closestApproach(s.Position.Minus(&t.Position),
+		s.Velocity.Minus(&t.Velocity), s.Acceleration.Minus(&t.Acceleration))
+}
+
+type Threat struct {
+	Ship *Ship
+	Time, Distance float64
+}
+
+type byDanger []Threat
+
+func (t byDanger) Len() int { return len(t) }
+func (t byDanger) Less(i, j int) bool {
+	if t[i].Distance != t[j].Distance { return t[i].Distance < t[j].Distance }
+	return t[i].Time < t[j].Time
+}
+func (t byDanger) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+
+// Rank the other ships by how closely they will pass s, nearest miss first
+// and sooner before later.
+func (s *Ship) Threats(ships []*Ship, accelerating bool) []Threat {
+	var threats []Threat
+	for _, o := range ships {
+		if o == s { continue }
+		th := Threat{Ship: o}
+		if accelerating {
+			th.Time, th.Distance = s.ClosestApproachAccelerating(o)
+		} else {
+			th.Time, th.Distance = s.ClosestApproach(o)
+		}
+		threats = append(threats, th)
+	}
+	sort.Sort(byDanger(threats))
+	return threats
+}
@@ -0,0 +1,68 @@
+package lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCubicRoots(t *testing.T) {
+	// (x - 1)(x - 2)(x + 3) = x^3 - 7x + 6
+	roots := cubicRoots(1, 0, -7, 6)
+	if len(roots) != 3 {
+		t.Fatalf("Expected three roots, got %v", roots)
+	}
+	for _, r := range roots {
+		if v := r*r*r - 7*r + 6; math.Abs(v) > 1e-9 {
+			t.Errorf("Root %v evaluates to %v", r, v)
+		}
+	}
+	if roots = cubicRoots(0, 0, 2, -4); len(roots) != 1 || roots[0] != 2 {
+		t.Errorf("Expected linear root 2, got %v", roots)
+	}
+}
+
+func TestClosestApproach(t *testing.T) {
+	s := &Ship{Velocity:Vector{X:1}}
+	o := &Ship{Position:Vector{10, 3, 0}}
+	time, d := s.ClosestApproach(o)
+	if !fequal(time, 10) || !fequal(d, 3) {
+		t.Errorf("Expected approach to 3 at 10s, got %v at %vs", d, time)
+	}
+	// Moving apart, the closest approach is now.
+	s.Velocity.X = -1
+	if time, d = s.ClosestApproach(o); time != 0 || d != o.Distance(s) {
+		t.Errorf("Expected closest approach now, got %v at %vs", d, time)
+	}
+}
+
+func TestClosestApproachAccelerating(t *testing.T) {
+	// Starting at rest, s reaches x = 8 after 4s at 1 m/s/s.
+	s := &Ship{Acceleration:Vector{X:1}}
+	o := &Ship{Position:Vector{8, 2, 0}}
+	time, d := s.ClosestApproachAccelerating(o)
+	if math.Abs(time - 4) > 1e-9 || math.Abs(d - 2) > 1e-9 {
+		t.Errorf("Expected approach to 2 at 4s, got %v at %vs", d, time)
+	}
+	// Without acceleration it never gets closer.
+	if time, _ = s.ClosestApproach(o); time != 0 {
+		t.Errorf("Expected a stationary ship to be closest now, got %vs", time)
+	}
+}
+
+func TestThreats(t *testing.T) {
+	s := &Ship{}
+	near := &Ship{Position:Vector{X:100}, Velocity:Vector{X:-10}}
+	far := &Ship{Position:Vector{X:10, Y:5}}
+	threats := s.Threats([]*Ship{s, far, near}, false)
+	if len(threats) != 2 || threats[0].Ship != near || threats[1].Ship != far {
+		t.Errorf("Expected %v to be ranked before %v, got %v", near, far, threats)
+	}
+}
+
+func BenchmarkClosestApproachAccelerating(b *testing.B) {
+	s := &Ship{Velocity:Vector{1, 2, 3}, Acceleration:Vector{0.1, 0, 0.3}}
+	o := &Ship{Position:Vector{100, 30, 5}, Acceleration:Vector{-1, 0, 0}}
+	for i := 0; i < b.N; i++ {
+		s.ClosestApproachAccelerating(o)
+	}
+}
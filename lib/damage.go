@@ -0,0 +1,170 @@
+// Hull, shields and subsystem damage.
+
+package lib
+
+import (
+	"math"
+)
+
+type Facing int
+
+const (
+	Fore Facing = iota
+	Aft
+	Flank
+	Facings
+)
+
+type Subsystem int
+
+const (
+	Engines Subsystem = iota
+	WeaponSystems
+	Sensors
+	Subsystems
+)
+
+const (
+	// Fraction of the hull lost at which a ship is disabled.
+	disableAt = 0.75
+	// Weapons go offline once damaged this badly.
+	weaponsOfflineAt = 0.5
+	// Subsystem damage taken per unit of hull damage (as a fraction of Hull).
+	fragility = 2.0
+)
+
+// A shield screening one facing of the ship. Drain regenerates at Regen
+// points per second up to Strength.
+type Shield struct {
+	Strength, Regen, Drain float64
+}
+
+// The subsystem knocked out by hull damage taken through each facing.
+var exposed = [Facings]Subsystem{Sensors, Engines, WeaponSystems}
+
+// Return which of s's facings looks toward p. Ships face along their
+// velocity, or along X when stationary.
+func (s *Ship) Facing(p *Vector) Facing {
+	heading := s.Velocity
+	if heading.IsZero() { heading.X = 1 }
+	to := p.Minus(&s.Position)
+	if to.IsZero() { return Fore }
+	switch c := heading.Dot(to) / heading.Length() / to.Length(); {
+	case c > 0.5: return Fore
+	case c < -0.5: return Aft
+	}
+	return Flank
+}
+
+// Return the hull points remaining. Ships with no Hull are indestructible.
+func (s *Ship) HullPoints() float64 { return s.Hull - s.HullDamage }
+
+func (s *Ship) Destroyed() bool {
+	return s.Hull > 0 && s.HullDamage >= s.Hull
+}
+
+// A disabled ship is adrift: badly holed or with its engines shot out.
+func (s *Ship) Disabled() bool {
+	return s.Hull > 0 && s.HullDamage >= disableAt * s.Hull ||
+		s.Systems[Engines] >= 1
+}
+
+// Return the most the engines can accelerate the ship, less when damaged or
+// laden; unlimited when MaxAcceleration is zero.
+func (s *Ship) Thrust() float64 {
+	if s.Disabled() { return 0 }
+	if s.MaxAcceleration == 0 { return math.Inf(1) }
+	a := s.MaxAcceleration * (1 - s.Systems[Engines])
+	if s.Mass > 0 { a *= s.Mass / s.TotalMass() }
+	return a
+}
+
+// Maneuvers that need a finite acceleration to aim with fly ships of
+// unlimited thrust at this.
+const unlimitedSteering = 100.0
+
+// Return the acceleration to maneuver at: the ship's thrust, but never
+// unlimited.
+func (s *Ship) steeringThrust() float64 {
+	return math.Min(s.Thrust(), unlimitedSteering)
+}
+
+func (s *Ship) WeaponsOnline() bool {
+	return !s.Disabled() && s.Systems[WeaponSystems] < weaponsOfflineAt
+}
+
+// Return the range of the ship's sensors, shortened by damage.
+func (s *Ship) SensorRange() float64 {
+	return s.Range * (1 - s.Systems[Sensors])
+}
+
+// Apply damage arriving from p, returning the damage that got through the
+// shields to the hull.
+func (s *Ship) TakeDamage(amount float64, p *Vector) float64 {
+	if s.Hull <= 0 { return 0 }
+	f := s.Facing(p)
+	shield := &s.Shields[f]
+	absorbed := math.Min(amount, shield.Strength - shield.Drain)
+	if absorbed > 0 {
+		shield.Drain += absorbed
+		amount -= absorbed
+	}
+	if amount <= 0 { return 0 }
+	s.HullDamage += amount
+	sys := &s.Systems[exposed[f]]
+	*sys = math.Min(1, *sys + fragility * amount / s.Hull)
+	return amount
+}
+
+// Recharge the ship's shields over t seconds.
+func (s *Ship) regenerate(t float64) {
+	for i := range s.Shields {
+		sh := &s.Shields[i]
+		sh.Drain = math.Max(0, sh.Drain - sh.Regen*t)
+	}
+}
+
+// Hold the ship's acceleration within what its engines can deliver.
+func (s *Ship) limitThrust() {
+	if a := s.Thrust(); s.Acceleration.SquaredLength() > a*a {
+		s.Acceleration.ScaleToInPlace(a)
+	}
+}
+
+// Apply the hits among events, returning them interleaved with the damage
+// they caused and any ships disabled or destroyed as a result.
+func (w *World) resolve(events []Event) []Event {
+	var out []Event
+	for _, e := range events {
+		out = append(out, e)
+		if e.Kind != Hit && e.Kind != Detonation || e.Ship.Destroyed() {
+			continue
+		}
+		from := e.Ship.Position.Plus(&e.Impact)
+		disabled := e.Ship.Disabled()
+		hull := e.Ship.TakeDamage(e.Damage, from)
+		if hull == 0 { continue }
+		out = append(out, Event{Kind: Damaged, Time: e.Time, Ship: e.Ship,
+			Other: e.Other, Damage: hull})
+		if e.Ship.Destroyed() {
+			out = append(out, Event{Kind: Destroyed, Time: e.Time, Ship: e.Ship,
+				Other: e.Other})
+			w.Remove(e.Ship)
+		} else if !disabled && e.Ship.Disabled() {
+			e.Ship.Acceleration = Vector{}
+			out = append(out, Event{Kind: Disabled, Time: e.Time, Ship: e.Ship,
+				Other: e.Other})
+		}
+	}
+	return out
+}
+
+// Take s out of the world.
+func (w *World) Remove(s *Ship) {
+	for i, o := range w.Ships {
+		if o == s {
+			w.Ships = append(w.Ships[:i], w.Ships[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestFacing(t *testing.T) {
+	s := &Ship{Velocity:Vector{Y:5}}
+	for p, f := range map[Vector]Facing{
+		Vector{Y:10}: Fore, Vector{Y:-10}: Aft, Vector{X:10}: Flank,
+		Vector{10, -1, 0}: Flank, Vector{-1, -10, 2}: Aft,
+	} {
+		if g := s.Facing(&p); g != f {
+			t.Errorf("Expected %v to lie on facing %v, got %v", p, f, g)
+		}
+	}
+}
+
+func TestShieldsAbsorbAndRegenerate(t *testing.T) {
+	s := &Ship{Hull:100}
+	s.Shields[Fore] = Shield{Strength:10, Regen:2}
+	front := &Vector{X:10}
+	if hull := s.TakeDamage(8, front); hull != 0 || s.Shields[Fore].Drain != 8 {
+		t.Errorf("Shield should absorb the hit; hull took %v", hull)
+	}
+	if hull := s.TakeDamage(8, front); hull != 6 || s.HullPoints() != 94 {
+		t.Errorf("Expected 6 damage through the shield, got %v", hull)
+	}
+	if hull := s.TakeDamage(1, &Vector{X:-10}); hull != 1 {
+		t.Errorf("Unshielded aft facing should take the hit, got %v", hull)
+	}
+	s.regenerate(3)
+	if d := s.Shields[Fore].Drain; d != 4 {
+		t.Errorf("Expected shield drain to recover to 4, got %v", d)
+	}
+}
+
+func TestSubsystemDamage(t *testing.T) {
+	s := &Ship{Hull:100, MaxAcceleration:10, Range:50}
+	s.TakeDamage(10, &Vector{X:-1})
+	if a := s.Thrust(); a != 8 {
+		t.Errorf("Expected damaged engines to give 8 m/s/s, got %v", a)
+	}
+	s.TakeDamage(10, &Vector{X:1})
+	if r := s.SensorRange(); r != 40 {
+		t.Errorf("Expected damaged sensors to reach 40, got %v", r)
+	}
+	s.TakeDamage(30, &Vector{Y:1})
+	if s.WeaponsOnline() {
+		t.Error("Weapons should be offline.")
+	}
+	if s.Disabled() {
+		t.Error("Ship disabled too soon.")
+	}
+	if (&Ship{}).TakeDamage(10, &Vector{}) != 0 {
+		t.Error("Ship without a hull took damage.")
+	}
+}
+
+func TestDamageEvents(t *testing.T) {
+	beam := &Weapon{Kind:Beam, Range:100, Damage:40}
+	s := &Ship{Weapons:[]*Weapon{beam}}
+	target := &Ship{Position:Vector{X:10}, Hull:100, MaxAcceleration:5,
+		Acceleration:Vector{Y:5}}
+	w := &World{Ships:[]*Ship{s, target}}
+	var kinds []EventKind
+	for i := 0; i < 3; i++ {
+		w.Fire(s, beam, target)
+		for _, e := range w.Step(1) { kinds = append(kinds, e.Kind) }
+		if i == 1 && !target.Acceleration.IsZero() {
+			t.Error("Disabled ship is still accelerating.")
+		}
+	}
+	expect := []EventKind{Hit, Damaged, Hit, Damaged, Disabled, Hit, Damaged,
+		Destroyed}
+	if len(kinds) != len(expect) {
+		t.Fatalf("Expected events %v, got %v", expect, kinds)
+	}
+	for i := range expect {
+		if kinds[i] != expect[i] {
+			t.Fatalf("Expected events %v, got %v", expect, kinds)
+		}
+	}
+	if len(w.Ships) != 1 {
+		t.Error("Destroyed ship was not removed.")
+	}
+}
+
+func TestImpactFacing(t *testing.T) {
+	// The shooter has got ahead of its shot, which strikes from astern.
+	shooter := &Ship{Position:Vector{X:100}}
+	target := &Ship{Radius:1, Hull:100}
+	target.Shields[Aft].Strength = 10
+	shot := &Projectile{Position:Vector{X:-50}, Velocity:Vector{X:100},
+		Owner:shooter, Damage:5, TTL:10}
+	w := &World{Ships:[]*Ship{shooter, target}, Projectiles:[]*Projectile{shot}}
+	events := w.Step(1)
+	if len(events) == 0 || events[0].Kind != Hit || events[0].Impact.X >= 0 {
+		t.Fatalf("Expected a hit from astern, got %v", events)
+	}
+	if target.HullDamage != 0 || target.Shields[Aft].Drain != 5 {
+		t.Errorf("Expected the aft shield to take the hit, hull took %v",
+			target.HullDamage)
+	}
+}
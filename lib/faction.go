@@ -0,0 +1,82 @@
+// Factions: empires owning systems, their fleets and their diplomacy.
+
+package lib
+
+type Relation int
+
+const (
+	Peace Relation = iota
+	War
+	Alliance
+)
+
+type Fleet struct {
+	Name string
+	Ships []*Ship
+}
+
+type Faction struct {
+	Name string
+	Knowledge *Knowledge
+	Treasury float64
+	Fleets []*Fleet
+	// Standing of ships with the faction; below zero they are wanted.
+	Reputation map[*Ship]float64
+	// Goods it is a crime to trade in the faction's systems.
+	Banned map[*Commodity]bool
+	relations map[*Faction]Relation
+}
+
+func NewFaction(name string) *Faction {
+	return &Faction{Name: name, Knowledge: NewKnowledge(),
+		Reputation: make(map[*Ship]float64), Banned: make(map[*Commodity]bool),
+		relations: make(map[*Faction]Relation)}
+}
+
+// Return f's standing with g. Factions are at peace unless they say
+// otherwise, and always allied with themselves.
+func (f *Faction) Relation(g *Faction) Relation {
+	if f == g { return Alliance }
+	return f.relations[g]
+}
+
+// Enlist s into the named fleet, forming it if need be.
+func (f *Faction) Commission(s *Ship, fleet string) *Fleet {
+	s.Faction = f
+	for _, fl := range f.Fleets {
+		if fl.Name == fleet {
+			fl.Ships = append(fl.Ships, s)
+			return fl
+		}
+	}
+	fl := &Fleet{fleet, []*Ship{s}}
+	f.Fleets = append(f.Fleets, fl)
+	return fl
+}
+
+// Strike s from f's fleets, e.g. when it is lost or captured.
+func (f *Faction) Decommission(s *Ship) {
+	for _, fl := range f.Fleets {
+		for i, o := range fl.Ships {
+			if o == s { fl.Ships = append(fl.Ships[:i], fl.Ships[i+1:]...) }
+		}
+	}
+	if s.Faction == f { s.Faction = nil }
+}
+
+// Return every ship in f's fleets.
+func (f *Faction) Ships() []*Ship {
+	var ships []*Ship
+	for _, fl := range f.Fleets { ships = append(ships, fl.Ships...) }
+	return ships
+}
+
+// Update the faction's knowledge with what its ships in w, a world around
+// the given star, can currently see. Its own ships are always known.
+func (f *Faction) Observe(w *World, star *Star) {
+	for _, s := range w.Ships {
+		if s.Faction != f { continue }
+		f.Knowledge.Observe(star, []Contact{{w.Time, s.Position, s.Velocity, s}})
+		f.Knowledge.Observe(star, w.Scan(s))
+	}
+}
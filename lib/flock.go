@@ -0,0 +1,168 @@
+// Fleets moving together: boids-style flocking and formation flying.
+
+package lib
+
+import (
+	"math"
+)
+
+// Return the ships of the flock within r of s, other than s.
+func neighbours(s *Ship, flock []*Ship, r float64) []*Ship {
+	var near []*Ship
+	for _, o := range flock {
+		if o != s && s.Distance(o) <= r { near = append(near, o) }
+	}
+	return near
+}
+
+// Keep clear of flockmates within Radius, pushing harder the closer they
+// are, up to Acceleration.
+type Separation struct {
+	Flock []*Ship
+	Radius, Acceleration float64
+}
+
+func (b *Separation) Steer(s *Ship) Vector {
+	var push Vector
+	for _, o := range neighbours(s, b.Flock, b.Radius) {
+		away := s.Position.Minus(&o.Position)
+		if away.IsZero() { away.X = 1 }
+		push.AddWithScaleInPlace(away.Unit(), 1 - s.Distance(o) / b.Radius)
+	}
+	return truncate(*push.Times(b.Acceleration), b.Acceleration)
+}
+
+// Match the average velocity of flockmates within Radius.
+type Alignment struct {
+	Flock []*Ship
+	Radius, Acceleration float64
+}
+
+func (b *Alignment) Steer(s *Ship) Vector {
+	near := neighbours(s, b.Flock, b.Radius)
+	if len(near) == 0 { return Vector{} }
+	var v Vector
+	for _, o := range near { v.AddWithScaleInPlace(&o.Velocity, 1 / float64(len(near))) }
+	v.MinusInPlace(&s.Velocity)
+	return truncate(v, b.Acceleration)
+}
+
+// Close on the centre of flockmates within Radius.
+type Cohesion struct {
+	Flock []*Ship
+	Radius, Acceleration float64
+}
+
+func (b *Cohesion) Steer(s *Ship) Vector {
+	near := neighbours(s, b.Flock, b.Radius)
+	if len(near) == 0 { return Vector{} }
+	var p, v Vector
+	for _, o := range near {
+		p.AddWithScaleInPlace(&o.Position, 1 / float64(len(near)))
+		v.AddWithScaleInPlace(&o.Velocity, 1 / float64(len(near)))
+	}
+	return Maneuver(func(s *Ship) { s.Arrive(&p, &v, b.Acceleration) }).Steer(s)
+}
+
+const (
+	// Arrivals plan to brake at this fraction of the acceleration, to have
+	// some in hand, and aim to correct their velocity within this many
+	// seconds, which should be a few World steps. The last stretch is eased
+	// in, closing no faster than the distance left each arrivalSettle.
+	arrivalBraking = 0.8
+	arrivalResponse = 0.25
+	arrivalSettle = 1.0
+)
+
+// Make for point p, moving with velocity v, to arrive there at rest
+// relative to it: close as fast as acceleration a can still brake from,
+// and accelerate to match that.
+func (s *Ship) Arrive(p, v *Vector, a float64) {
+	toward := p.Minus(&s.Position)
+	d := toward.Length()
+	want := *toward.ScaleTo(math.Min(math.Sqrt(2 * arrivalBraking * a * d),
+		d / arrivalSettle))
+	want.PlusInPlace(v)
+	want.MinusInPlace(&s.Velocity)
+	s.Acceleration = truncate(*want.Times(1 / arrivalResponse), a)
+}
+
+type FormationShape int
+
+const (
+	// Line astern of the leader.
+	Line FormationShape = iota
+	// Alternately to either side of the leader, further back each pair.
+	Wedge
+	// Spread evenly over a sphere around the leader.
+	Sphere
+)
+
+type Formation struct {
+	Leader *Ship
+	Shape FormationShape
+	// Distance between neighbouring slots, or the radius of a Sphere.
+	Spacing float64
+	Members []*Ship
+}
+
+// Return the leader's frame: its heading, or +X when stationary, and two
+// directions square to it.
+func (f *Formation) frame() (fore, right, up Vector) {
+	if fore = *f.Leader.Velocity.Unit(); fore.IsZero() { fore = Vector{X: 1} }
+	if right = *fore.Cross(&Vector{Z: 1}); right.IsZero() { right = Vector{Y: -1} }
+	right = *right.Unit()
+	up = *right.Cross(&fore)
+	return
+}
+
+// Return where the i-th member's slot is, relative to the leader in its
+// frame: fore, right and up.
+func (f *Formation) Slot(i int) Vector {
+	k := float64(i + 1)
+	switch f.Shape {
+	case Wedge:
+		k = float64(i / 2 + 1)
+		side := k
+		if i % 2 == 1 { side = -k }
+		return Vector{-k * f.Spacing, side * f.Spacing, 0}
+	case Sphere:
+		// Points on a Fibonacci spiral, pole to pole.
+		n := float64(len(f.Members))
+		z := 1 - (2 * float64(i) + 1) / n
+		r, phi := math.Sqrt(1 - z*z), float64(i) * math.Pi * (3 - math.Sqrt(5))
+		return *(&Vector{z, r * math.Cos(phi), r * math.Sin(phi)}).Times(f.Spacing)
+	}
+	return Vector{-k * f.Spacing, 0, 0}
+}
+
+// Return the i-th member's slot in the world.
+func (f *Formation) Position(i int) Vector {
+	fore, right, up := f.frame()
+	slot := f.Slot(i)
+	p := f.Leader.Position
+	p.AddWithScaleInPlace(&fore, slot.X)
+	p.AddWithScaleInPlace(&right, slot.Y)
+	p.AddWithScaleInPlace(&up, slot.Z)
+	return p
+}
+
+// A behavior for the i-th member to take and hold its slot with
+// acceleration a, however the leader maneuvers.
+func (f *Formation) Keep(i int, a float64) Behavior {
+	return Maneuver(func(s *Ship) {
+		p := f.Position(i)
+		s.Arrive(&p, &f.Leader.Velocity, a)
+		s.Acceleration.PlusInPlace(&f.Leader.Acceleration)
+	})
+}
+
+// Set every member to fly its slot with acceleration a, keeping clear of
+// the others first.
+func (f *Formation) Fly(a float64) {
+	flock := append([]*Ship{f.Leader}, f.Members...)
+	for i, s := range f.Members {
+		s.SetController(&SteeringController{s, Priority{
+			&Separation{flock, f.Spacing / 2, a}, f.Keep(i, a)}})
+	}
+}
@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFlocking(t *testing.T) {
+	a := &Ship{Velocity:Vector{X:2}}
+	b := &Ship{Position:Vector{X:1}, Velocity:Vector{Y:2}}
+	c := &Ship{Position:Vector{X:20}}
+	flock := []*Ship{a, b, c}
+	if v := (&Separation{flock, 5, 1}).Steer(a); v.X >= 0 || v.Y != 0 {
+		t.Errorf("Expected a to move away from b alone, got %v", v)
+	}
+	if v := (&Alignment{flock, 5, 10}).Steer(a); v != (Vector{-2, 2, 0}) {
+		t.Errorf("Expected a to match b's velocity, got %v", v)
+	}
+	if v := (&Cohesion{flock, 50, 1}).Steer(c); v.X >= 0 || !fequal(v.Length(), 1) {
+		t.Errorf("Expected c to close on the others, got %v", v)
+	}
+	if v := (&Cohesion{flock, 5, 1}).Steer(c); !v.IsZero() {
+		t.Errorf("Expected c to have no flockmates, got %v", v)
+	}
+}
+
+func TestArrive(t *testing.T) {
+	s := &Ship{}
+	w := &World{}
+	w.Add(s)
+	p, v := Vector{X:100}, Vector{Y:1}
+	s.SetController(&SteeringController{s,
+		Maneuver(func(s *Ship) { s.Arrive(&p, &v, 2) })})
+	for i := 0; i < 400; i++ {
+		w.Step(0.1)
+		p.AddWithScaleInPlace(&v, 0.1)
+	}
+	if s.Position.Distance(&p) > 0.1 || s.Velocity.Distance(&v) > 0.1 {
+		t.Errorf("Expected to arrive at %v moving %v, got %v moving %v", p, v,
+			s.Position, s.Velocity)
+	}
+}
+
+func TestFormation(t *testing.T) {
+	leader := &Ship{Velocity:Vector{Y:5}}
+	f := &Formation{Leader:leader, Shape:Wedge, Spacing:10}
+	w := &World{}
+	w.Add(leader)
+	for i := 0; i < 4; i++ {
+		s := &Ship{Position:Vector{X:float64(i * 3)}}
+		f.Members = append(f.Members, s)
+		w.Add(s)
+	}
+	if slot := f.Position(1); slot != (Vector{-10, -10, 0}) {
+		t.Errorf("Expected the second slot back and to the left, got %v", slot)
+	}
+	f.Fly(3)
+	for i := 0; i < 600; i++ {
+		// The leader turns halfway through.
+		if i == 300 { leader.Velocity = Vector{X:5} }
+		w.Step(0.1)
+	}
+	for i, s := range f.Members {
+		if slot := f.Position(i); s.Position.Distance(&slot) > 0.5 ||
+			s.Velocity.Distance(&leader.Velocity) > 0.1 {
+			t.Errorf("Member %v out of formation at %v, slot %v", i,
+				s.Position, slot)
+		}
+	}
+	f.Shape = Sphere
+	for i := range f.Members {
+		if slot := f.Slot(i); math.Abs(slot.Length() - 10) > 1e-9 {
+			t.Errorf("Expected slot %v on the sphere, got %v", i, slot)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// The state of a whole game: the star map, its systems and who owns them.
+
+package lib
+
+import (
+	"sort"
+)
+
+// Credits a system pays its owner each second.
+const tribute = 0.1
+
+type Game struct {
+	Time float64
+	Stars []*Star
+	Lanes *JumpGraph
+	Factions []*Faction
+	// Systems by star Id, created as they are first visited.
+	Systems map[int]*System
+	// Every crime on record and the bounties still open.
+	Offences []Offence
+	Bounties []*Bounty
+	owners map[int]*Faction
+	// How much of each system's market log has been checked for smuggling.
+	judged map[int]int
+}
+
+func NewGame(lanes *JumpGraph) *Game {
+	return &Game{Stars: lanes.Stars, Lanes: lanes,
+		Systems: make(map[int]*System), owners: make(map[int]*Faction),
+		judged: make(map[int]int)}
+}
+
+// Return the system around star, bringing it into play if need be.
+func (g *Game) System(star *Star) *System {
+	s := g.Systems[star.Id]
+	if s == nil {
+		s = NewSystem(star)
+		s.World.Time = g.Time
+		s.Market.Time = g.Time
+		g.Systems[star.Id] = s
+	}
+	return s
+}
+
+func (g *Game) AddFaction(name string) *Faction {
+	f := NewFaction(name)
+	g.Factions = append(g.Factions, f)
+	return f
+}
+
+func (g *Game) Faction(name string) *Faction {
+	for _, f := range g.Factions {
+		if f.Name == name { return f }
+	}
+	return nil
+}
+
+// Hand the system around star to f (or to nobody, if f is nil).
+func (g *Game) Claim(f *Faction, star *Star) {
+	if f == nil {
+		delete(g.owners, star.Id)
+		return
+	}
+	g.owners[star.Id] = f
+	f.Knowledge.Discover(star)
+}
+
+// Return the faction owning the system around star, or nil.
+func (g *Game) Owner(star *Star) *Faction { return g.owners[star.Id] }
+
+// Return the stars whose systems f owns.
+func (g *Game) Holdings(f *Faction) []*Star {
+	var stars []*Star
+	for _, s := range g.Stars {
+		if g.owners[s.Id] == f { stars = append(stars, s) }
+	}
+	return stars
+}
+
+func (g *Game) Relation(a, b *Faction) Relation { return a.Relation(b) }
+
+// Set relations between a and b, which are always mutual.
+func (g *Game) SetRelation(a, b *Faction, r Relation) {
+	if a == b { return }
+	a.relations[b], b.relations[a] = r, r
+}
+
+// Report whether ships of a and b should fight: their factions are at war,
+// or either is wanted by the other's faction.
+func (g *Game) Hostile(a, b *Ship) bool {
+	if a.Faction != nil && a.Faction.Wanted(b) >= patrolLevel ||
+		b.Faction != nil && b.Faction.Wanted(a) >= patrolLevel {
+		return true
+	}
+	return a.Faction != nil && b.Faction != nil &&
+		a.Faction.Relation(b.Faction) == War
+}
+
+// Return the system s is flying in, or nil if it is not in play.
+func (g *Game) Locate(s *Ship) *System {
+	if s.Drive != nil && s.Drive.System != nil { return s.Drive.System }
+	for _, sys := range g.Systems {
+		for _, t := range sys.World.Ships {
+			if t == s { return sys }
+		}
+	}
+	return nil
+}
+
+// Advance every system in play by dt seconds, paying owners their tribute,
+// letting every faction see what its ships there can see, and returning
+// what happened in each system, by star Id.
+func (g *Game) Step(dt float64) map[int][]Event {
+	events := make(map[int][]Event)
+	var ids []int
+	for id := range g.Systems { ids = append(ids, id) }
+	sort.Ints(ids)
+	for _, id := range ids {
+		s := g.Systems[id]
+		if e := s.Step(dt); len(e) > 0 { events[id] = e }
+		g.enforce(s, events[id])
+		for _, f := range g.Factions { f.Observe(s.World, s.Star) }
+	}
+	for _, f := range g.owners { f.Treasury += tribute * dt }
+	g.Time += dt
+	return events
+}
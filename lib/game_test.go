@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestFactions(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0, 1, 2), 1.5))
+	empire, rebels := g.AddFaction("Empire"), g.AddFaction("Rebels")
+	if g.Faction("Rebels") != rebels || g.Faction("Pirates") != nil {
+		t.Error("Factions not found by name.")
+	}
+	g.Claim(empire, g.Stars[0])
+	g.Claim(empire, g.Stars[1])
+	g.Claim(rebels, g.Stars[1])
+	if h := g.Holdings(empire); len(h) != 1 || h[0] != g.Stars[0] {
+		t.Errorf("Expected the empire to hold only star 0, got %v", h)
+	}
+	if g.Owner(g.Stars[1]) != rebels || g.Owner(g.Stars[2]) != nil {
+		t.Error("Unexpected owners.")
+	}
+	if g.Relation(empire, rebels) != Peace {
+		t.Error("Factions should start at peace.")
+	}
+	g.SetRelation(empire, rebels, War)
+	if g.Relation(rebels, empire) != War {
+		t.Error("Relations should be mutual.")
+	}
+	destroyer, xwing := &Ship{}, &Ship{}
+	empire.Commission(destroyer, "Home Fleet")
+	rebels.Commission(xwing, "Red Squadron")
+	if !g.Hostile(destroyer, xwing) || g.Hostile(destroyer, destroyer) {
+		t.Error("Ships of warring factions should be hostile.")
+	}
+	empire.Decommission(destroyer)
+	if len(empire.Ships()) != 0 || destroyer.Faction != nil {
+		t.Error("Decommissioned ship still in the fleet.")
+	}
+}
+
+func TestGameStep(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0, 1), 1.5))
+	empire := g.AddFaction("Empire")
+	g.Claim(empire, g.Stars[0])
+	home := g.System(g.Stars[0])
+	s := &Ship{Range:10}
+	empire.Commission(s, "Home Fleet")
+	home.World.Add(s)
+	stranger := &Ship{Position:Vector{X:5}}
+	home.World.Add(stranger)
+	for i := 0; i < 10; i++ { g.Step(1) }
+	if !fequal(empire.Treasury, 10 * tribute) || g.Time != 10 {
+		t.Errorf("Expected a treasury of %v, got %v", 10 * tribute,
+			empire.Treasury)
+	}
+	if sighting, ok := empire.Knowledge.LastSeen(stranger); !ok ||
+		sighting.Time != 10 {
+		t.Errorf("Expected the stranger to be seen at 10s, got %v", sighting)
+	}
+	// Factions learn from their ships in systems they do not own.
+	rebels := g.AddFaction("Rebels")
+	scout := &Ship{Range:10}
+	rebels.Commission(scout, "Scouts")
+	home.World.Add(scout)
+	g.Step(1)
+	if sighting, ok := rebels.Knowledge.LastSeen(stranger); !ok ||
+		sighting.Time != 11 {
+		t.Errorf("Expected the scout to see the stranger, got %v", sighting)
+	}
+	if g.System(g.Stars[0]) != home || len(g.Systems) != 1 {
+		t.Error("Systems should be created once, on demand.")
+	}
+}
@@ -0,0 +1,140 @@
+// Hyperlanes: a sparse, navigable strategic map carved out of the catalog.
+
+package lib
+
+import (
+	"math/rand"
+	"sort"
+)
+
+type LaneMode int
+
+const (
+	// The relative neighbourhood graph: lanes (p, q) with no star closer to
+	// both p and q than they are to each other.
+	Neighbourhood LaneMode = iota
+	// A minimum spanning tree of the above, for long winding routes.
+	SpanningTree
+)
+
+type HyperlaneConfig struct {
+	// The playable map is the stars within Radius parsecs of Center, at
+	// most MaxStars of them nearest first. Zero values impose no limit.
+	Center *Star
+	Radius float64
+	MaxStars int
+	// No lane is longer than this many parsecs; zero imposes no limit.
+	MaxLane float64
+	Mode LaneMode
+	// Fraction of the neighbourhood lanes left out by Mode to add back,
+	// picked at random.
+	Extra float64
+	Seed int64
+}
+
+type starsByDistance struct {
+	stars []*Star
+	from *Star
+}
+
+func (s starsByDistance) Len() int { return len(s.stars) }
+func (s starsByDistance) Less(i, j int) bool {
+	return s.from.Distance(s.stars[i]) < s.from.Distance(s.stars[j])
+}
+func (s starsByDistance) Swap(i, j int) {
+	s.stars[i], s.stars[j] = s.stars[j], s.stars[i]
+}
+
+type edge struct {
+	i, j int
+	d float64
+}
+
+type byLength []edge
+
+func (e byLength) Len() int { return len(e) }
+func (e byLength) Less(i, j int) bool {
+	if e[i].d != e[j].d { return e[i].d < e[j].d }
+	return e[i].i < e[j].i || e[i].i == e[j].i && e[i].j < e[j].j
+}
+func (e byLength) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+// Choose the playable stars from the catalog.
+func (c *HyperlaneConfig) playable(stars []*Star) []*Star {
+	var chosen []*Star
+	for _, s := range stars {
+		if c.Radius == 0 || c.Center == nil || c.Center.Distance(s) <= c.Radius {
+			chosen = append(chosen, s)
+		}
+	}
+	if c.Center != nil { sort.Stable(starsByDistance{chosen, c.Center}) }
+	if c.MaxStars > 0 && len(chosen) > c.MaxStars { chosen = chosen[:c.MaxStars] }
+	return chosen
+}
+
+// Return the relative neighbourhood graph of stars, restricted to edges
+// of at most r parsecs, shortest first. Every such edge is also a Delaunay
+// edge, so this is the Delaunay triangulation pruned to the RNG, found from
+// nearby pairs directly rather than by triangulating in three dimensions.
+func neighbourhoodGraph(stars []*Star, r float64) []edge {
+	near := make([][]int, len(stars))
+	var candidates []edge
+	nearbyPairs(stars, r, func(i, j int) {
+		near[i] = append(near[i], j)
+		near[j] = append(near[j], i)
+		candidates = append(candidates, edge{i, j, stars[i].Distance(stars[j])})
+	})
+	var rng []edge
+	for _, e := range candidates {
+		empty := true
+		// Any witness is within e.d of both ends, so it is near e.i.
+		for _, k := range near[e.i] {
+			if k != e.j && stars[e.i].Distance(stars[k]) < e.d &&
+				stars[e.j].Distance(stars[k]) < e.d {
+				empty = false
+				break
+			}
+		}
+		if empty { rng = append(rng, e) }
+	}
+	sort.Sort(byLength(rng))
+	return rng
+}
+
+func find(parent []int, i int) int {
+	for parent[i] != i {
+		parent[i] = parent[parent[i]]
+		i = parent[i]
+	}
+	return i
+}
+
+// Split edges, shortest first, into a minimum spanning forest and the rest.
+func spanningForest(n int, edges []edge) (tree, rest []edge) {
+	parent := make([]int, n)
+	for i := range parent { parent[i] = i }
+	for _, e := range edges {
+		if a, b := find(parent, e.i), find(parent, e.j); a != b {
+			parent[a] = b
+			tree = append(tree, e)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+	return tree, rest
+}
+
+// Build hyperlanes over the playable part of the catalog. The same stars
+// and config always give the same map.
+func Hyperlanes(stars []*Star, c *HyperlaneConfig) *JumpGraph {
+	g := newJumpGraph(c.playable(stars))
+	rng := neighbourhoodGraph(g.Stars, c.MaxLane)
+	lanes, rest := rng, []edge(nil)
+	if c.Mode == SpanningTree { lanes, rest = spanningForest(len(g.Stars), rng) }
+	r := rand.New(rand.NewSource(c.Seed))
+	for _, e := range rest {
+		if r.Float64() < c.Extra { lanes = append(lanes, e) }
+	}
+	for _, e := range lanes { g.connect(e.i, e.j) }
+	return g
+}
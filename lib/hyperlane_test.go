@@ -0,0 +1,87 @@
+package lib
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomStars(n int, seed int64) []*Star {
+	r := rand.New(rand.NewSource(seed))
+	var stars []*Star
+	for i := 0; i < n; i++ {
+		stars = append(stars, &Star{Id:i, X:r.Float64() * 20,
+			Y:r.Float64() * 20, Z:r.Float64() * 20})
+	}
+	return stars
+}
+
+func laneCount(g *JumpGraph) int {
+	n := 0
+	for _, lanes := range g.Lanes { n += len(lanes) }
+	return n / 2
+}
+
+func TestNeighbourhoodGraph(t *testing.T) {
+	stars := randomStars(200, 1)
+	g := Hyperlanes(stars, &HyperlaneConfig{MaxLane:8})
+	for i, lanes := range g.Lanes {
+		for _, l := range lanes {
+			if l.Length > 8 {
+				t.Fatalf("Lane of %vpc exceeds the cap", l.Length)
+			}
+			for _, k := range g.Stars {
+				if k.Distance(g.Stars[i]) < l.Length &&
+					k.Distance(g.Stars[l.To]) < l.Length {
+					t.Fatalf("Lane %v-%v has a star between its ends", i, l.To)
+				}
+			}
+		}
+	}
+	if n := laneCount(g); n < len(stars) - 1 || n > 3*len(stars) {
+		t.Errorf("Expected a sparse connected map, got %v lanes", n)
+	}
+}
+
+func TestUncappedLanes(t *testing.T) {
+	g := Hyperlanes(starLine(0, 1, 2, 10), &HyperlaneConfig{})
+	if n := laneCount(g); n != 3 || g.Range != 8 {
+		t.Errorf("Expected a chain of 3 lanes up to 8pc, got %v", g.Lanes)
+	}
+}
+
+func TestSpanningTreeLanes(t *testing.T) {
+	stars := randomStars(200, 2)
+	c := &HyperlaneConfig{MaxLane:8, Mode:SpanningTree}
+	tree := Hyperlanes(stars, c)
+	if n := laneCount(tree); n != len(stars) - 1 {
+		t.Errorf("Expected a spanning tree of %v lanes, got %v", len(stars)-1, n)
+	}
+	if _, err := tree.Route(stars[0], stars[199], &RoutePlan{}); err != nil {
+		t.Errorf("Spanning tree is not connected: %v", err)
+	}
+	c.Extra, c.Seed = 0.5, 7
+	a, b := Hyperlanes(stars, c), Hyperlanes(stars, c)
+	if laneCount(a) <= laneCount(tree) || laneCount(a) != laneCount(b) {
+		t.Errorf("Expected extra lanes, deterministically: %v, %v",
+			laneCount(a), laneCount(b))
+	}
+	for i := range a.Lanes {
+		for k := range a.Lanes[i] {
+			if a.Lanes[i][k] != b.Lanes[i][k] {
+				t.Fatalf("Same seed produced different maps at star %v", i)
+			}
+		}
+	}
+}
+
+func TestHyperlanePlayableStars(t *testing.T) {
+	stars := starLine(0, 1, 2, 3, 4, 50)
+	c := &HyperlaneConfig{Center:stars[2], Radius:10, MaxStars:3, MaxLane:5}
+	g := Hyperlanes(stars, c)
+	if len(g.Stars) != 3 || g.Stars[0] != stars[2] {
+		t.Fatalf("Expected the three stars nearest the center, got %v", g.Stars)
+	}
+	if laneCount(g) != 2 {
+		t.Errorf("Expected two lanes, got %v", g.Lanes)
+	}
+}
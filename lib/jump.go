@@ -0,0 +1,101 @@
+// Jump drives, which carry ships between star systems.
+
+package lib
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoDrive = errors.New("ship has no jump drive")
+	ErrOutOfRange = errors.New("destination is beyond jump range")
+	ErrInsufficientFuel = errors.New("not enough fuel to jump")
+	ErrDisabled = errors.New("ship is disabled")
+	ErrUncharted = errors.New("system has no star")
+)
+
+type JumpDrive struct {
+	// Longest single jump, in parsecs.
+	Range float64
+	// Seconds spent charging before each jump.
+	ChargeTime float64
+	// Fuel burned on every jump, plus per parsec jumped.
+	FuelPerJump, FuelPerParsec float64
+	// Where the ship is now, and where it is headed.
+	System, Destination *System
+	charge float64
+}
+
+// Return the fuel a jump from the drive's current system to star will burn.
+func (d *JumpDrive) Cost(star *Star) (float64, error) {
+	if d.System == nil || d.System.Star == nil || star == nil {
+		return 0, ErrUncharted
+	}
+	return d.FuelPerJump + d.FuelPerParsec * d.System.Star.Distance(star), nil
+}
+
+// Start charging the jump drive for to; the ship jumps once it is charged.
+func (s *Ship) Jump(to *System) error {
+	d := s.Drive
+	if d == nil { return ErrNoDrive }
+	if s.Disabled() { return ErrDisabled }
+	if to == nil { return ErrUncharted }
+	cost, err := d.Cost(to.Star)
+	if err != nil { return err }
+	if d.System.Star.Distance(to.Star) > d.Range { return ErrOutOfRange }
+	if cost > s.Fuel { return ErrInsufficientFuel }
+	d.Destination, d.charge = to, 0
+	return nil
+}
+
+func (s *Ship) CancelJump() {
+	if s.Drive != nil { s.Drive.Destination = nil }
+}
+
+// Return the fraction of the jump charge built up so far.
+func (d *JumpDrive) Charged() float64 {
+	if d.Destination == nil { return 0 }
+	if d.ChargeTime <= 0 { return 1 }
+	return d.charge / d.ChargeTime
+}
+
+// Charge the drives of ships in w for t seconds, jumping those that are
+// ready out of w and into their destination, and report the departures.
+func (w *World) chargeDrives(t float64) []Event {
+	var events []Event
+	var jumpers []*Ship
+	for _, s := range w.Ships {
+		if d := s.Drive; d != nil && d.Destination != nil {
+			if s.Disabled() {
+				d.Destination = nil
+				continue
+			}
+			if d.charge += t; d.charge >= d.ChargeTime {
+				jumpers = append(jumpers, s)
+			}
+		}
+	}
+	for _, s := range jumpers {
+		d := s.Drive
+		from, to := d.System, d.Destination
+		cost, err := d.Cost(to.Star)
+		if err != nil {
+			d.Destination = nil
+			continue
+		}
+		s.Fuel -= cost
+		d.System, d.Destination, d.charge = to, nil, 0
+		// Drop out at the edge of the system, on the side facing home.
+		x, y, z := from.Star.Position()
+		back := Vector{x - to.Star.X, y - to.Star.Y, z - to.Star.Z}
+		if back.IsZero() { back.X = 1 }
+		s.Position = *back.ScaleTo(to.Edge)
+		s.Velocity, s.Acceleration = Vector{}, Vector{}
+		w.Remove(s)
+		to.World.Add(s)
+		events = append(events, Event{Kind: Departed, Time: w.Time + t, Ship: s})
+		to.World.pending = append(to.World.pending,
+			Event{Kind: Arrived, Time: to.World.Time, Ship: s})
+	}
+	return events
+}
@@ -0,0 +1,61 @@
+package lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJump(t *testing.T) {
+	sol := NewSystem(&Star{Name:"Sol"})
+	proxima := NewSystem(&Star{Id:1, Name:"Proxima Centauri", X:0.9, Y:-0.9})
+	far := NewSystem(&Star{Id:2, X:10})
+	s := &Ship{Fuel:2, Drive:&JumpDrive{Range:5, ChargeTime:10,
+		FuelPerParsec:2, System:sol}}
+	sol.World.Add(s)
+	if err := (&Ship{}).Jump(proxima); err != ErrNoDrive {
+		t.Errorf("Expected %v, got %v", ErrNoDrive, err)
+	}
+	if err := s.Jump(far); err != ErrOutOfRange {
+		t.Errorf("Expected %v, got %v", ErrOutOfRange, err)
+	}
+	if err := s.Jump(proxima); err != ErrInsufficientFuel {
+		t.Errorf("Expected %v, got %v", ErrInsufficientFuel, err)
+	}
+	if err := s.Jump(&System{}); err != ErrUncharted {
+		t.Errorf("Expected %v, got %v", ErrUncharted, err)
+	}
+	adrift := &Ship{Fuel:10, Drive:&JumpDrive{Range:5}}
+	if err := adrift.Jump(proxima); err != ErrUncharted {
+		t.Errorf("Expected %v, got %v", ErrUncharted, err)
+	}
+	s.Fuel = 10
+	if err := s.Jump(proxima); err != nil {
+		t.Fatalf("Jump failed: %v", err)
+	}
+	for i := 0; i < 9; i++ {
+		if events := sol.World.Step(1); len(events) != 0 {
+			t.Fatalf("Jumped before charging: %v", events)
+		}
+	}
+	if c := s.Drive.Charged(); !fequal(c, 0.9) {
+		t.Errorf("Expected drive 90%% charged, got %v", c)
+	}
+	events := sol.World.Step(1)
+	if len(events) != 1 || events[0].Kind != Departed || events[0].Ship != s {
+		t.Fatalf("Expected to depart, got %v", events)
+	}
+	if len(sol.World.Ships) != 0 || s.Drive.System != proxima {
+		t.Error("Ship is still in Sol.")
+	}
+	if !fequal(s.Fuel, 10 - 2*proxima.Star.Distance(sol.Star)) {
+		t.Errorf("Unexpected fuel left after jump: %v", s.Fuel)
+	}
+	edge := Vector{-DefaultEdge / math.Sqrt2, DefaultEdge / math.Sqrt2, 0}
+	if s.Position.Distance(&edge) > 1e-6 {
+		t.Errorf("Expected to arrive at %v, got %v", edge, s.Position)
+	}
+	events = proxima.World.Step(1)
+	if len(events) != 1 || events[0].Kind != Arrived || events[0].Ship != s {
+		t.Errorf("Expected to arrive, got %v", events)
+	}
+}
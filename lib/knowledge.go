@@ -0,0 +1,88 @@
+// What a faction knows of the galaxy, as opposed to how it really is.
+
+package lib
+
+// Where a ship was last seen, and when.
+type Sighting struct {
+	Time float64
+	Star *Star
+	Position Vector
+	Velocity Vector
+}
+
+// Market prices as they stood when last reported.
+type PriceReport struct {
+	Time float64
+	Prices map[string]float64
+}
+
+// A faction's view of the galaxy. Everything in it is only as fresh as the
+// last report that reached it.
+type Knowledge struct {
+	Ships map[*Ship]Sighting
+	// Star systems from the catalog that have been discovered, by Id.
+	Stars map[int]*Star
+	Markets map[int]PriceReport
+}
+
+func NewKnowledge() *Knowledge {
+	return &Knowledge{Ships: make(map[*Ship]Sighting),
+		Stars: make(map[int]*Star), Markets: make(map[int]PriceReport)}
+}
+
+func (k *Knowledge) Discover(s *Star) { k.Stars[s.Id] = s }
+
+func (k *Knowledge) Discovered(s *Star) bool { return k.Stars[s.Id] != nil }
+
+// Record contacts made in the given star system, keeping only the newest
+// sighting of each ship.
+func (k *Knowledge) Observe(star *Star, contacts []Contact) {
+	k.Discover(star)
+	for _, c := range contacts {
+		if old, ok := k.Ships[c.Ship]; ok && old.Time > c.Time { continue }
+		k.Ships[c.Ship] = Sighting{c.Time, star, c.Position, c.Velocity}
+	}
+}
+
+// Forget a ship, e.g. once it is known to be destroyed.
+func (k *Knowledge) Forget(s *Ship) { delete(k.Ships, s) }
+
+func (k *Knowledge) LastSeen(s *Ship) (Sighting, bool) {
+	sighting, ok := k.Ships[s]
+	return sighting, ok
+}
+
+// Return where s is believed to be at time now, dead reckoning from its
+// last sighting.
+func (k *Knowledge) Estimate(s *Ship, now float64) (*Vector, bool) {
+	sighting, ok := k.Ships[s]
+	if !ok { return nil, false }
+	p := sighting.Position
+	p.AddWithScaleInPlace(&sighting.Velocity, now - sighting.Time)
+	return &p, true
+}
+
+// Return the ships last seen in the given system no longer ago than maxAge.
+func (k *Knowledge) SeenAt(star *Star, now, maxAge float64) []*Ship {
+	var ships []*Ship
+	for s, sighting := range k.Ships {
+		if sighting.Star == star && now - sighting.Time <= maxAge {
+			ships = append(ships, s)
+		}
+	}
+	return ships
+}
+
+func (k *Knowledge) ReportPrices(star *Star, now float64, prices map[string]float64) {
+	k.Discover(star)
+	report := PriceReport{now, make(map[string]float64)}
+	for c, p := range prices { report.Prices[c] = p }
+	k.Markets[star.Id] = report
+}
+
+// Return the last known prices at star and how old they are.
+func (k *Knowledge) Prices(star *Star, now float64) (map[string]float64, float64, bool) {
+	report, ok := k.Markets[star.Id]
+	if !ok { return nil, 0, false }
+	return report.Prices, now - report.Time, true
+}
@@ -0,0 +1,57 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestKnowledgeSightings(t *testing.T) {
+	sol, proxima := &Star{Name:"Sol"}, &Star{Id:1, Name:"Proxima Centauri"}
+	us, them := NewFaction("Us"), NewFaction("Them")
+	scout := &Ship{Range:100, Faction:us}
+	raider := &Ship{Position:Vector{X:50}, Velocity:Vector{Y:2}, Faction:them}
+	hidden := &Ship{Position:Vector{X:500}, Faction:them}
+	w := &World{Time:10, Ships:[]*Ship{scout, raider, hidden}}
+	us.Observe(w, sol)
+	if !us.Knowledge.Discovered(sol) || us.Knowledge.Discovered(proxima) {
+		t.Error("Expected to have discovered only Sol.")
+	}
+	if _, ok := us.Knowledge.LastSeen(scout); !ok {
+		t.Error("Faction does not know where its own ship is.")
+	}
+	if _, ok := us.Knowledge.LastSeen(hidden); ok {
+		t.Error("Faction knows of a ship it never detected.")
+	}
+	raider.Position.X = 1000
+	p, ok := us.Knowledge.Estimate(raider, 15)
+	if !ok || *p != (Vector{50, 10, 0}) {
+		t.Errorf("Expected to dead reckon the raider to (50, 10, 0), got %v", p)
+	}
+	if seen := us.Knowledge.SeenAt(sol, 15, 10); len(seen) != 2 {
+		t.Errorf("Expected two ships seen recently at Sol, got %v", seen)
+	}
+	if seen := us.Knowledge.SeenAt(sol, 100, 10); len(seen) != 0 {
+		t.Errorf("Expected sightings to have gone stale, got %v", seen)
+	}
+	us.Knowledge.Forget(raider)
+	if _, ok := us.Knowledge.LastSeen(raider); ok {
+		t.Error("Forgotten ship still known.")
+	}
+	if _, ok := them.Knowledge.LastSeen(scout); ok {
+		t.Error("Knowledge leaked between factions.")
+	}
+}
+
+func TestKnowledgePrices(t *testing.T) {
+	sol := &Star{Name:"Sol"}
+	k := NewKnowledge()
+	if _, _, ok := k.Prices(sol, 0); ok {
+		t.Error("Prices known for an unvisited market.")
+	}
+	prices := map[string]float64{"Food": 10}
+	k.ReportPrices(sol, 5, prices)
+	prices["Food"] = 20
+	known, age, ok := k.Prices(sol, 8)
+	if !ok || known["Food"] != 10 || age != 3 {
+		t.Errorf("Expected food at 10, 3s old; got %v, %vs old", known, age)
+	}
+}
@@ -0,0 +1,220 @@
+// Law and order: crimes, reputation, wanted ships and the bounties on them.
+
+package lib
+
+import (
+	"math"
+)
+
+type Crime int
+
+const (
+	// Striking a ship with a shot or missile, whether or not its shields
+	// hold.
+	Assault Crime = iota
+	// Destroying a ship.
+	Murder
+	// Boarding a ship.
+	Piracy
+	// Trading in goods banned where they are traded.
+	Smuggling
+)
+
+// Reputation lost with the victim's faction for each crime.
+var severity = [...]float64{Assault: 5, Murder: 50, Piracy: 20, Smuggling: 10}
+
+const (
+	// Reputation below zero for each wanted level, and the highest level.
+	wantedStep = 20.0
+	maxWanted = 5
+	// Wanted levels at which patrols give chase and bounties are posted.
+	patrolLevel = 1
+	bountyLevel = 2
+	// Credits offered for each wanted level.
+	bountyReward = 100.0
+)
+
+type Offence struct {
+	Time float64
+	Crime Crime
+	// The offender and victim, if there is one, and where it happened.
+	Ship, Victim *Ship
+	Star *Star
+}
+
+// Credits Issuer will pay whoever destroys or captures Target.
+type Bounty struct {
+	Issuer *Faction
+	Target *Ship
+	Reward float64
+}
+
+// Return how badly f wants s, from 0 (not at all) to maxWanted.
+func (f *Faction) Wanted(s *Ship) int {
+	r := f.Reputation[s]
+	if r >= 0 { return 0 }
+	level := int(math.Ceil(-r / wantedStep))
+	if level > maxWanted { return maxWanted }
+	return level
+}
+
+// Record an offence, costing the offender reputation with the victim's
+// faction and its allies and winning it some with the victim's enemies.
+// The owner of the system, whose law was broken, counts it as a crime
+// against itself unless it is at war with the victim. Police work is no
+// crime: ships wanted by the offender's faction or the owner are fair game.
+func (g *Game) Commit(o Offence) {
+	x := severity[o.Crime]
+	var victim, owner *Faction
+	if o.Victim != nil { victim = o.Victim.Faction }
+	if o.Star != nil { owner = g.owners[o.Star.Id] }
+	if o.Victim != nil && (o.Ship.Faction != nil &&
+		o.Ship.Faction.Wanted(o.Victim) >= patrolLevel ||
+		owner != nil && owner.Wanted(o.Victim) >= patrolLevel) {
+		return
+	}
+	g.Offences = append(g.Offences, o)
+	for _, f := range g.Factions {
+		d := 0.0
+		if victim != nil {
+			switch f.Relation(victim) {
+			case Alliance:
+				if d = -x / 2; f == victim { d = -x }
+			case War:
+				d = x / 2
+			}
+		}
+		if f == owner && (victim == nil || f.Relation(victim) != War) && d > -x {
+			d = -x
+		}
+		if d == 0 { continue }
+		f.Reputation[o.Ship] += d
+		level := f.Wanted(o.Ship)
+		if level >= patrolLevel { g.dispatch(f, o.Ship, o.Star) }
+		if level >= bountyLevel {
+			g.post(f, o.Ship, bountyReward * float64(level))
+		}
+	}
+}
+
+// Send f's patrols around star after suspect, unless they are already
+// after someone more wanted.
+func (g *Game) dispatch(f *Faction, suspect *Ship, star *Star) {
+	if star == nil || g.Systems[star.Id] == nil { return }
+	for _, s := range g.Systems[star.Id].World.Ships {
+		p, ok := s.c.(*Patrol)
+		if !ok || s.Faction != f || p.Suspect == suspect { continue }
+		if p.Suspect == nil || f.Wanted(p.Suspect) < f.Wanted(suspect) {
+			p.Suspect = suspect
+		}
+	}
+}
+
+// Post a bounty on target, or raise the one already posted.
+func (g *Game) post(issuer *Faction, target *Ship, reward float64) {
+	if b := g.Bounty(issuer, target); b != nil {
+		if reward > b.Reward { b.Reward = reward }
+		return
+	}
+	g.Bounties = append(g.Bounties, &Bounty{issuer, target, reward})
+}
+
+// Return issuer's open bounty on target, or nil.
+func (g *Game) Bounty(issuer *Faction, target *Ship) *Bounty {
+	for _, b := range g.Bounties {
+		if b.Issuer == issuer && b.Target == target { return b }
+	}
+	return nil
+}
+
+// Pay hunter every bounty on target, as far as the issuers' treasuries go.
+func (g *Game) claim(target, hunter *Ship) {
+	var open []*Bounty
+	for _, b := range g.Bounties {
+		if b.Target != target {
+			open = append(open, b)
+			continue
+		}
+		if hunter == nil || hunter == target { continue }
+		reward := math.Min(b.Reward, b.Issuer.Treasury)
+		b.Issuer.Treasury -= reward
+		hunter.Credits += reward
+	}
+	g.Bounties = open
+}
+
+// Take note of the crimes in what happened in s this step, and pay out
+// bounties on the ships destroyed.
+func (g *Game) enforce(s *System, events []Event) {
+	for _, e := range events {
+		switch e.Kind {
+		case Hit, Detonation:
+			if e.Other != nil {
+				g.Commit(Offence{e.Time, Assault, e.Other, e.Ship, s.Star})
+			}
+		case Destroyed:
+			if e.Other != nil {
+				g.Commit(Offence{e.Time, Murder, e.Other, e.Ship, s.Star})
+			}
+			g.claim(e.Ship, e.Other)
+		}
+	}
+	// Judge each trade once, whenever it was made.
+	owner, log := g.owners[s.Star.Id], s.Market.Log
+	for _, tr := range log[g.judged[s.Star.Id]:] {
+		if owner != nil && owner.Banned[tr.Commodity] {
+			g.Commit(Offence{tr.Time, Smuggling, tr.Ship, nil, s.Star})
+		}
+	}
+	g.judged[s.Star.Id] = len(log)
+}
+
+// Board t from s, as World.Board does, but within the law: boarding is
+// piracy, and a captured ship's bounties are paid to its captor.
+func (g *Game) Board(s, t *Ship, prize Prize) (*Boarding, error) {
+	sys := g.Locate(s)
+	if sys == nil || g.Locate(t) != sys { return nil, ErrTooFar }
+	// Judge the crime before the victim changes hands.
+	if err := s.CanBoard(t); err != nil { return nil, err }
+	g.Commit(Offence{sys.World.Time, Piracy, s, t, sys.Star})
+	b, err := sys.World.Board(s, t, prize)
+	if err == nil && b.Won && prize == SeizeShip { g.claim(t, s) }
+	return b, err
+}
+
+// A police ship. It holds station until it sees a ship its faction wants,
+// or its faction sends it after one, then runs down the most wanted and
+// fires on it.
+type Patrol struct {
+	Ship *Ship
+	World *World
+	// The ship the faction sent the patrol after, hunted seen or unseen
+	// until it is no longer wanted or leaves the system.
+	Suspect *Ship
+}
+
+func (p *Patrol) Redirect() {
+	s := p.Ship
+	// A patrol without a faction has no one to hunt.
+	if s.Faction == nil {
+		s.Acceleration = *s.Velocity.Times(-1)
+		return
+	}
+	if p.Suspect != nil && (s.Faction.Wanted(p.Suspect) < patrolLevel ||
+		!p.World.contains(p.Suspect)) {
+		p.Suspect = nil
+	}
+	suspect, most := p.Suspect, patrolLevel - 1
+	if suspect != nil { most = s.Faction.Wanted(suspect) }
+	for _, c := range p.World.Scan(s) {
+		if level := s.Faction.Wanted(c.Ship); level > most {
+			suspect, most = c.Ship, level
+		}
+	}
+	if suspect == nil {
+		s.Acceleration = *s.Velocity.Times(-1)
+		return
+	}
+	s.Approach(suspect, s.steeringThrust())
+	for _, w := range s.Weapons { p.World.Fire(s, w, suspect) }
+}
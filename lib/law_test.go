@@ -0,0 +1,214 @@
+package lib
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestReputation(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0), 1))
+	guild, navy := g.AddFaction("Guild"), g.AddFaction("Navy")
+	pirates, empire := g.AddFaction("Pirates"), g.AddFaction("Empire")
+	g.SetRelation(guild, navy, Alliance)
+	g.SetRelation(guild, pirates, War)
+	g.Claim(empire, g.Stars[0])
+	raider, merchant := &Ship{}, &Ship{}
+	guild.Commission(merchant, "Convoy")
+	g.Commit(Offence{0, Murder, raider, merchant, g.Stars[0]})
+	for f, r := range map[*Faction]float64{guild: -50, navy: -25, pirates: 25,
+		empire: -50} {
+		if f.Reputation[raider] != r {
+			t.Errorf("Expected %v to rate the raider %v, got %v", f.Name, r,
+				f.Reputation[raider])
+		}
+	}
+	if guild.Wanted(raider) != 3 || pirates.Wanted(raider) != 0 {
+		t.Errorf("Expected wanted level 3, got %v", guild.Wanted(raider))
+	}
+	if b := g.Bounty(guild, raider); b == nil || b.Reward != 3 * bountyReward {
+		t.Errorf("Expected a bounty from the guild, got %v", b)
+	}
+	if len(g.Bounties) != 3 || len(g.Offences) != 1 {
+		t.Errorf("Expected 3 bounties, got %v", g.Bounties)
+	}
+}
+
+func TestBountyHunting(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0), 1))
+	guild, hunters := g.AddFaction("Guild"), g.AddFaction("Hunters")
+	guild.Treasury = 1000
+	raider := &Ship{Position:Vector{X:5}, Hull:10}
+	hunter := &Ship{Hull:10}
+	hunters.Commission(hunter, "Posse")
+	g.System(g.Stars[0]).World.Add(raider)
+	g.System(g.Stars[0]).World.Add(hunter)
+	guild.Reputation[raider] = -50
+	hunters.Reputation[raider] = -50
+	g.post(guild, raider, 300)
+	beam := &Weapon{Kind:Beam, Range:10, Damage:20}
+	if !g.System(g.Stars[0]).World.Fire(hunter, beam, raider) {
+		t.Fatal("Failed to fire.")
+	}
+	g.Step(1)
+	if !raider.Destroyed() || hunter.Credits != 300 || guild.Treasury != 700 {
+		t.Errorf("Expected the bounty paid, got %v", hunter.Credits)
+	}
+	if len(g.Bounties) != 0 || len(g.Offences) != 0 ||
+		hunters.Reputation[hunter] != 0 {
+		t.Error("Killing a wanted ship should be no crime.")
+	}
+}
+
+func TestSmuggling(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0), 1))
+	empire := g.AddFaction("Empire")
+	empire.Banned[Luxuries] = true
+	g.Claim(empire, g.Stars[0])
+	s := &Ship{Hold:Hold{Capacity:100, Goods:map[*Commodity]float64{
+		Luxuries:1, Ore:1}}}
+	m := g.System(g.Stars[0]).Market
+	g.Step(1)
+	m.Sell(s, Ore, 1)
+	m.Sell(s, Luxuries, 1)
+	g.Step(1)
+	if len(g.Offences) != 1 || g.Offences[0].Crime != Smuggling ||
+		empire.Reputation[s] != -severity[Smuggling] {
+		t.Errorf("Expected one count of smuggling, got %v", g.Offences)
+	}
+	g.Step(1)
+	if len(g.Offences) != 1 { t.Error("Smuggling counted twice.") }
+}
+
+// A controller that sells its whole hold on its first tick.
+type fence struct {
+	ship *Ship
+	market *Market
+}
+
+func (f *fence) Redirect() {
+	for c, q := range f.ship.Hold.Goods { f.market.Sell(f.ship, c, q) }
+}
+
+func TestSmugglingDuringStep(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0), 1))
+	empire := g.AddFaction("Empire")
+	empire.Banned[Luxuries] = true
+	g.Claim(empire, g.Stars[0])
+	sys := g.System(g.Stars[0])
+	s := &Ship{Hold:Hold{Capacity:100, Goods:map[*Commodity]float64{Luxuries:1}}}
+	s.SetController(&fence{s, sys.Market})
+	sys.World.Add(s)
+	for i := 0; i < 3; i++ { g.Step(1) }
+	if len(g.Offences) != 1 || empire.Reputation[s] != -severity[Smuggling] {
+		t.Errorf("Expected one count of smuggling, got %v", g.Offences)
+	}
+}
+
+func TestShieldedAssault(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0), 1))
+	guild := g.AddFaction("Guild")
+	w := g.System(g.Stars[0]).World
+	raider, merchant := &Ship{}, &Ship{Position:Vector{X:5}, Hull:10}
+	merchant.Shields[Aft] = Shield{Strength:10}
+	guild.Commission(merchant, "Convoy")
+	w.Add(raider)
+	w.Add(merchant)
+	w.Fire(raider, &Weapon{Kind:Beam, Range:10, Damage:1}, merchant)
+	g.Step(1)
+	if merchant.HullDamage != 0 || len(g.Offences) != 1 ||
+		g.Offences[0].Crime != Assault {
+		t.Errorf("Expected a shot on the shields to be assault, got %v",
+			g.Offences)
+	}
+}
+
+func TestPiracy(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0), 1))
+	guild, hunters := g.AddFaction("Guild"), g.AddFaction("Hunters")
+	guild.Treasury = 1000
+	pirate := &Ship{Radius:5, Crew:20}
+	hunters.Commission(pirate, "Posse")
+	merchant := &Ship{Position:Vector{X:15}, Radius:5, Crew:2}
+	merchant.Systems[Engines] = 1
+	guild.Commission(merchant, "Convoy")
+	g.System(g.Stars[0]).World.Add(pirate)
+	g.System(g.Stars[0]).World.Add(merchant)
+	guild.Reputation[merchant] = -40
+	hunters.Reputation[merchant] = -40
+	g.post(guild, merchant, 200)
+	if _, err := g.Board(pirate, merchant, SeizeShip); err != nil { t.Fatal(err) }
+	// The hunters wanted the merchant, so this was no piracy.
+	if pirate.Credits != 200 || guild.Reputation[pirate] != 0 {
+		t.Errorf("Expected the bounty paid, got %v", pirate.Credits)
+	}
+	hunters.Decommission(merchant)
+	delete(hunters.Reputation, merchant)
+	guild.Commission(merchant, "Convoy")
+	merchant.Crew, merchant.Credits = 2, 10
+	if _, err := g.Board(pirate, merchant, SeizeCredits); err != nil { t.Fatal(err) }
+	if guild.Reputation[pirate] != -severity[Piracy] || len(g.Offences) != 1 {
+		t.Errorf("Expected piracy on record, got %v", g.Offences)
+	}
+}
+
+func TestPatrol(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0), 1))
+	empire := g.AddFaction("Empire")
+	w := g.System(g.Stars[0]).World
+	cop := &Ship{Range:100, MaxAcceleration:1,
+		Weapons:[]*Weapon{{Kind:Beam, Range:20, Damage:1}}}
+	empire.Commission(cop, "Patrol")
+	cop.SetController(&Patrol{Ship:cop, World:w})
+	crook := &Ship{Position:Vector{X:15}, Hull:10}
+	bystander := &Ship{Position:Vector{Y:5}}
+	w.Add(cop)
+	w.Add(crook)
+	w.Add(bystander)
+	g.Step(1)
+	if !cop.Velocity.IsZero() { t.Error("Patrol should hold station.") }
+	empire.Reputation[crook] = -1
+	g.Step(1)
+	if cop.Velocity.X <= 0 || crook.HullDamage != 1 {
+		t.Errorf("Expected the patrol to chase and fire, got %v", cop.Velocity)
+	}
+	if !g.Hostile(cop, crook) || g.Hostile(cop, bystander) {
+		t.Error("Expected only the crook to be hostile.")
+	}
+	// A patrol with no faction has no one to chase, and does not look.
+	rogue := &Ship{Range:100, SensorNoise:0.1, Velocity:Vector{Y:1}}
+	w.Add(rogue)
+	w.Rand = rand.New(rand.NewSource(1))
+	(&Patrol{Ship:rogue, World:w}).Redirect()
+	if rogue.Acceleration != (Vector{Y:-1}) {
+		t.Errorf("Expected the rogue to hold station, got %v", rogue.Acceleration)
+	}
+	if w.Rand.Int63() != rand.New(rand.NewSource(1)).Int63() {
+		t.Error("The rogue patrol scanned.")
+	}
+}
+
+func TestDispatchPatrol(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0), 1))
+	empire := g.AddFaction("Empire")
+	g.Claim(empire, g.Stars[0])
+	w := g.System(g.Stars[0]).World
+	// Unlimited thrust, and sensors that see nothing.
+	cop := &Ship{Range:1e-6}
+	empire.Commission(cop, "Patrol")
+	p := &Patrol{Ship:cop, World:w}
+	cop.SetController(p)
+	crook := &Ship{Position:Vector{X:1000}}
+	w.Add(cop)
+	w.Add(crook)
+	g.Step(1)
+	if !cop.Velocity.IsZero() { t.Error("Patrol should hold station unseen.") }
+	g.Commit(Offence{0, Piracy, crook, nil, g.Stars[0]})
+	if p.Suspect != crook { t.Fatalf("Expected the patrol sent after the crook") }
+	g.Step(1)
+	if v := cop.Velocity; v.X <= 0 || v.X > unlimitedSteering {
+		t.Errorf("Expected the patrol to give chase, got %v", v)
+	}
+	empire.Reputation[crook] = 0
+	g.Step(1)
+	if p.Suspect != nil { t.Error("Expected the patrol called off.") }
+}
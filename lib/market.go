@@ -0,0 +1,113 @@
+// Commodity markets whose prices follow supply and demand.
+
+package lib
+
+import (
+	"math"
+)
+
+type Commodity struct {
+	Name string
+	BasePrice float64
+	// Tonnes per unit.
+	Mass float64
+}
+
+var (
+	Food = &Commodity{"Food", 10, 1}
+	Water = &Commodity{"Water", 4, 1}
+	Ore = &Commodity{"Ore", 15, 2}
+	Metals = &Commodity{"Metals", 40, 1.5}
+	Fuel = &Commodity{"Fuel", 20, 0.8}
+	Machinery = &Commodity{"Machinery", 120, 1}
+	Electronics = &Commodity{"Electronics", 250, 0.2}
+	Luxuries = &Commodity{"Luxuries", 400, 0.1}
+	Commodities = []*Commodity{Food, Water, Ore, Metals, Fuel, Machinery,
+		Electronics, Luxuries}
+)
+
+const (
+	// How strongly price responds to a shortage or glut.
+	elasticity = 0.8
+	// Prices stay within this factor of the base price either way.
+	priceSpread = 5.0
+	// Seconds of trade that a market keeps in stock at equilibrium.
+	stockpile = 3600.0
+)
+
+// Units produced (positive) or consumed (negative) per second by a planet.
+var planetTrade = map[BodyKind]map[*Commodity]float64{
+	Rocky: {Ore: 0.2, Metals: 0.1, Machinery: 0.02, Food: -0.05, Water: -0.05},
+	Ocean: {Food: 0.3, Water: 0.2, Electronics: -0.02, Machinery: -0.03,
+		Luxuries: -0.01, Metals: -0.05},
+	Ice: {Water: 0.3, Food: -0.02, Fuel: -0.02},
+	GasGiant: {Fuel: 0.25, Machinery: -0.02, Electronics: 0.005},
+}
+
+// Production multipliers by spectral type: hot stars power fuel skimming,
+// dim red dwarfs leave little but mining.
+var stellarTrade = map[byte]map[*Commodity]float64{
+	'O': {Fuel: 3}, 'B': {Fuel: 2.5}, 'A': {Fuel: 2},
+	'G': {Food: 1.5}, 'K': {Food: 1.2},
+	'M': {Ore: 1.5, Food: 0.5},
+}
+
+type Stock struct {
+	Quantity float64
+	// Units per second.
+	Production, Consumption float64
+	// Quantity at which goods sell at their base price.
+	Equilibrium float64
+}
+
+type Market struct {
+	Time float64
+	Goods map[*Commodity]*Stock
+	Log []Transaction
+}
+
+// Open a market trading what the star's planets produce and need.
+func NewMarket(star *Star, planets []*Body) *Market {
+	m := &Market{Goods: make(map[*Commodity]*Stock)}
+	for _, c := range Commodities { m.Goods[c] = &Stock{} }
+	for _, p := range planets {
+		for c, rate := range planetTrade[p.Kind] {
+			if rate > 0 {
+				if f, ok := stellarTrade[spectralType(star)][c]; ok { rate *= f }
+				m.Goods[c].Production += rate
+			} else {
+				m.Goods[c].Consumption -= rate
+			}
+		}
+	}
+	for _, s := range m.Goods {
+		s.Equilibrium = stockpile * (s.Production + s.Consumption) + 100
+		s.Quantity = s.Equilibrium
+	}
+	return m
+}
+
+// Return the price of one unit: dear when stocks run short, cheap in a glut.
+func (m *Market) Price(c *Commodity) float64 {
+	s := m.Goods[c]
+	if s == nil { return 0 }
+	ratio := math.Pow(s.Equilibrium / math.Max(s.Quantity, 1), elasticity)
+	return c.BasePrice * math.Max(1/priceSpread, math.Min(priceSpread, ratio))
+}
+
+// Return the current prices by commodity name, as reported to factions.
+func (m *Market) Prices() map[string]float64 {
+	prices := make(map[string]float64)
+	for c := range m.Goods { prices[c.Name] = m.Price(c) }
+	return prices
+}
+
+// Run production and consumption for t seconds. Warehouses hold at most
+// priceSpread times the equilibrium stock.
+func (m *Market) Update(t float64) {
+	m.Time += t
+	for _, s := range m.Goods {
+		s.Quantity += (s.Production - s.Consumption) * t
+		s.Quantity = math.Max(0, math.Min(priceSpread * s.Equilibrium, s.Quantity))
+	}
+}
@@ -0,0 +1,61 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestGeneratePlanets(t *testing.T) {
+	sol := &Star{Name:"Sol", Class:"G2V"}
+	a, b := GeneratePlanets(sol), GeneratePlanets(sol)
+	if len(a) < 2 || len(a) != len(b) {
+		t.Fatalf("Expected the same planets each time, got %v and %v", a, b)
+	}
+	for i := range a {
+		if *a[i] != *b[i] {
+			t.Errorf("Planet %v differs: %v and %v", i, a[i], b[i])
+		}
+		if a[i].Position.Length() >= DefaultEdge {
+			t.Errorf("Planet %v lies beyond the system edge", a[i])
+		}
+	}
+}
+
+func TestMarketPrices(t *testing.T) {
+	star := &Star{Class:"K1V"}
+	m := NewMarket(star, []*Body{{Kind:Rocky}, {Kind:Ocean}})
+	for _, c := range Commodities {
+		if p := m.Price(c); !fequal(p, c.BasePrice) {
+			t.Errorf("%v opens at %v, expected %v", c.Name, p, c.BasePrice)
+		}
+	}
+	// Rocky and ocean worlds both produce water but only ocean worlds
+	// produce food, which the rocky world eats.
+	if s := m.Goods[Food]; !fequal(s.Production, 0.36) || s.Consumption != 0.05 {
+		t.Errorf("Unexpected food production %v", s)
+	}
+	m.Update(3600)
+	if m.Price(Food) >= Food.BasePrice {
+		t.Errorf("Food glut should lower its price, got %v", m.Price(Food))
+	}
+	if m.Price(Electronics) <= Electronics.BasePrice {
+		t.Errorf("Electronics shortage should raise the price, got %v",
+			m.Price(Electronics))
+	}
+	m.Update(1e9)
+	if p := m.Price(Luxuries); p != Luxuries.BasePrice * priceSpread {
+		t.Errorf("Price should be capped at %v, got %v",
+			Luxuries.BasePrice * priceSpread, p)
+	}
+	if p := m.Prices()["Food"]; p != m.Price(Food) {
+		t.Errorf("Price report %v disagrees with market %v", p, m.Price(Food))
+	}
+}
+
+func TestStellarProduction(t *testing.T) {
+	rocky := []*Body{{Kind:Rocky}}
+	red := NewMarket(&Star{Class:"M4V"}, rocky)
+	yellow := NewMarket(&Star{Class:"G2V"}, rocky)
+	if red.Goods[Ore].Production <= yellow.Goods[Ore].Production {
+		t.Error("Red dwarf systems should mine more ore.")
+	}
+}
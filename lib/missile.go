@@ -0,0 +1,179 @@
+// Guided missiles and evading them.
+
+package lib
+
+import (
+	"math"
+)
+
+type Seeker int
+
+const (
+	ProportionalNavigation Seeker = iota
+	// Chase the target with Ship.Approach.
+	Pursuit
+)
+
+// Seconds to impact below which an evading ship stops corkscrewing and
+// breaks away from the missile.
+const breakAway = 3.0
+
+// A small, fast ship that flies itself into Target, steering within the
+// Thrust of its embedded Ship.
+type Missile struct {
+	Ship
+	Owner, Target *Ship
+	Seeker Seeker
+	// Navigation constant for proportional navigation, typically 3 to 5.
+	Gain float64
+	// Delta-v left in the tanks.
+	DeltaV float64
+	// Detonate on passing this close to the target's hull.
+	Fuse float64
+	Damage float64
+	// Seconds until the missile self-destructs.
+	Lifetime float64
+}
+
+// Launch m from s at the target.
+func (w *World) Launch(s *Ship, m *Missile, target *Ship) {
+	m.Position, m.Velocity, m.Owner, m.Target = s.Position, s.Velocity, s, target
+	w.Missiles = append(w.Missiles, m)
+}
+
+// Steer by proportional navigation at the given thrust: turn in proportion
+// to the rotation of the line of sight, then spend any thrust left over on
+// closing. Falls back on pursuit while the target is opening the range.
+func (m *Missile) navigate(thrust float64) {
+	r := m.Target.Position.Minus(&m.Position)
+	v := m.Target.Velocity.Minus(&m.Velocity)
+	rr := r.SquaredLength()
+	closing := -r.Dot(v) / math.Sqrt(rr)
+	if rr == 0 || closing <= 0 {
+		m.Approach(m.Target, thrust)
+		return
+	}
+	rotation := r.Cross(v).Times(1 / rr)
+	a := rotation.Cross(r.Unit()).Times(m.Gain * closing)
+	// Augment with the target's own acceleration across the line of sight.
+	a.AddWithScaleInPlace(m.Target.Acceleration.Reject(r), m.Gain/2)
+	if l := a.Length(); l >= thrust {
+		a.ScaleToInPlace(thrust)
+	} else {
+		a.AddWithScaleInPlace(r.Unit(), math.Sqrt(thrust*thrust - l*l))
+	}
+	m.Acceleration = *a
+}
+
+// Set the missile's acceleration for the next t seconds within its fuel.
+// Even with unlimited thrust it burns no more than its fuel allows.
+func (m *Missile) guide(t float64) {
+	if m.DeltaV <= 0 || m.Target == nil || t <= 0 {
+		m.Acceleration = Vector{}
+		return
+	}
+	thrust := math.Min(m.Thrust(), m.DeltaV / t)
+	switch m.Seeker {
+	case ProportionalNavigation: m.navigate(thrust)
+	case Pursuit: m.Approach(m.Target, thrust)
+	}
+	if burn := m.Acceleration.Length() * t; burn > m.DeltaV {
+		m.Acceleration.TimesInPlace(m.DeltaV / burn)
+	}
+	m.DeltaV -= m.Acceleration.Length() * t
+}
+
+// Report whether the missile passes within its fuse of the target over the
+// next t seconds, when, and where it is then relative to the target.
+func (m *Missile) fuse(t float64) (float64, Vector, bool) {
+	p, v, a := m.Position.Minus(&m.Target.Position),
+		m.Velocity.Minus(&m.Target.Velocity),
+		m.Acceleration.Minus(&m.Target.Acceleration)
+	when, _ := closestApproach(p, v, a)
+	if when > t { when = t }
+	p.AddWithScaleInPlace(v, when)
+	p.AddWithScaleInPlace(a, 0.5*when*when)
+	return when, *p, p.Length() <= m.Fuse + m.Target.Radius
+}
+
+// Guide, fly and detonate the world's missiles over t seconds. Missiles
+// whose targets are destroyed or gone fly on ballistic.
+func (w *World) moveMissiles(t float64) []Event {
+	var events []Event
+	live := w.Missiles[:0]
+	for _, m := range w.Missiles {
+		if m.Target != nil && !w.contains(m.Target) { m.Target = nil }
+		m.guide(t)
+		if m.Target != nil {
+			if when, at, hit := m.fuse(t); hit {
+				events = append(events, Event{Kind: Detonation, Time: w.Time + when,
+					Ship: m.Target, Other: m.Owner, Damage: m.Damage, Impact: at})
+				continue
+			}
+		}
+		m.Move(t)
+		if m.Lifetime -= t; m.Lifetime > 0 { live = append(live, m) }
+	}
+	for i := len(live); i < len(w.Missiles); i++ { w.Missiles[i] = nil }
+	w.Missiles = live
+	return events
+}
+
+// Return the missiles s's sensors pick up, as copies standing where they
+// appear to be.
+func (w *World) ScanMissiles(s *Ship) []*Missile {
+	var seen []*Missile
+	for _, m := range w.Missiles {
+		if c, ok := s.Sense(&m.Ship, w.Rand); ok {
+			e := *m
+			e.Position, e.Velocity = c.Position, c.Velocity
+			seen = append(seen, &e)
+		}
+	}
+	return seen
+}
+
+// Return the missile homing on s that will arrive soonest, with its time to
+// impact, or nil if none are closing.
+func (s *Ship) Incoming(missiles []*Missile) (*Missile, float64) {
+	var nearest *Missile
+	best := math.Inf(1)
+	for _, m := range missiles {
+		if m.Target != s { continue }
+		r := s.Position.Minus(&m.Position)
+		closing := -r.Dot(s.Velocity.Minus(&m.Velocity)) / r.Length()
+		if closing <= 0 { continue }
+		if eta := r.Length() / closing; eta < best { nearest, best = m, eta }
+	}
+	return nearest, best
+}
+
+// Dodge the most pressing missile homing on s: corkscrew around its line of
+// approach to make it work for the intercept, then spiral away when it is
+// about to hit. Returns false when there is nothing to evade.
+func (s *Ship) Evade(missiles []*Missile, a float64) bool {
+	m, eta := s.Incoming(missiles)
+	if m == nil { return false }
+	if eta < breakAway {
+		s.SpiralAway(&m.Ship, a)
+	} else {
+		s.Corkscrew(&m.Ship, a)
+	}
+	return true
+}
+
+// Evade the missiles Ship sees launched at it, otherwise hand over to
+// Otherwise.
+type EvasionController struct {
+	Ship *Ship
+	World *World
+	Acceleration float64
+	Otherwise Controller
+}
+
+func (e *EvasionController) Redirect() {
+	missiles := e.World.ScanMissiles(e.Ship)
+	if !e.Ship.Evade(missiles, e.Acceleration) && e.Otherwise != nil {
+		e.Otherwise.Redirect()
+	}
+}
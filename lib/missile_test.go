@@ -0,0 +1,109 @@
+package lib
+
+import (
+	"math"
+	"testing"
+)
+
+func flyMissile(t *testing.T, seeker Seeker) {
+	s := &Ship{}
+	target := &Ship{Position:Vector{1000, 200, 0}, Velocity:Vector{Y:30},
+		Radius:5}
+	m := &Missile{Ship:Ship{MaxAcceleration:50}, Seeker:seeker, Gain:4,
+		DeltaV:2000, Fuse:2, Damage:40, Lifetime:60}
+	w := &World{Ships:[]*Ship{s, target}}
+	w.Launch(s, m, target)
+	for i := 0; i < 3000 && len(w.Missiles) > 0; i++ {
+		for _, e := range w.Step(0.01) {
+			if e.Kind == Detonation && e.Ship == target && e.Other == s &&
+				e.Damage == 40 {
+				return
+			}
+		}
+	}
+	t.Errorf("Missile missed; last seen at %v", m.Position)
+}
+
+func TestProportionalNavigation(t *testing.T) { flyMissile(t, ProportionalNavigation) }
+
+func TestPursuit(t *testing.T) { flyMissile(t, Pursuit) }
+
+func TestUnlimitedMissile(t *testing.T) {
+	for _, seeker := range []Seeker{ProportionalNavigation, Pursuit} {
+		target := &Ship{Position:Vector{1000, 200, 0}, Velocity:Vector{Y:30}}
+		m := &Missile{Seeker:seeker, Gain:4, DeltaV:100, Lifetime:60}
+		w := &World{Ships:[]*Ship{target}}
+		w.Launch(&Ship{}, m, target)
+		w.Step(0.1)
+		p, v := m.Position, m.Velocity
+		if math.IsNaN(p.X + p.Y + p.Z) || math.IsNaN(v.X + v.Y + v.Z) ||
+			math.Abs(v.Length() - 100) > 1e-9 || math.Abs(m.DeltaV) > 1e-9 {
+			t.Errorf("Seeker %v: expected to burn its 100 m/s at once, got %v " +
+				"with %v left", seeker, v, m.DeltaV)
+		}
+	}
+}
+
+func TestMissileLosesTarget(t *testing.T) {
+	target := &Ship{Position:Vector{X:1000}}
+	m := &Missile{Ship:Ship{MaxAcceleration:10}, DeltaV:100, Lifetime:60}
+	w := &World{Ships:[]*Ship{target}}
+	w.Launch(&Ship{}, m, target)
+	w.Step(1)
+	w.Remove(target)
+	w.Step(1)
+	if m.Target != nil || !m.Acceleration.IsZero() || m.DeltaV != 90 {
+		t.Errorf("Expected to fly on ballistic, burning %v with %v left",
+			m.Acceleration, m.DeltaV)
+	}
+}
+
+func TestMissileFuel(t *testing.T) {
+	target := &Ship{Position:Vector{X:1e6}}
+	m := &Missile{Ship:Ship{MaxAcceleration:10}, DeltaV:15, Lifetime:10}
+	w := &World{Ships:[]*Ship{target}}
+	w.Launch(&Ship{}, m, target)
+	for i := 0; i < 5; i++ { w.Step(1) }
+	if !fequal(m.Velocity.X, 15) || m.DeltaV != 0 {
+		t.Errorf("Expected to burn out at 15 m/s, going %v with %v left",
+			m.Velocity, m.DeltaV)
+	}
+	for i := 0; i < 5; i++ { w.Step(1) }
+	if len(w.Missiles) != 0 {
+		t.Error("Missile outlived its lifetime.")
+	}
+}
+
+func TestEvade(t *testing.T) {
+	s := &Ship{Velocity:Vector{X:1}}
+	if s.Evade(nil, 10) {
+		t.Error("Evading with no missiles about.")
+	}
+	far := &Missile{Ship:Ship{Position:Vector{Y:500}, Velocity:Vector{Y:-10}},
+		Target:s}
+	near := &Missile{Ship:Ship{Position:Vector{Y:100}, Velocity:Vector{Y:-50}},
+		Target:s}
+	other := &Missile{Ship:Ship{Position:Vector{Y:10}, Velocity:Vector{Y:-50}}}
+	missiles := []*Missile{far, near, other}
+	if m, eta := s.Incoming(missiles); m != near || !fequal(eta, 2) {
+		t.Errorf("Expected %v to hit in 2s, got %v in %vs", near, m, eta)
+	}
+	if !s.Evade(missiles, 10) || s.Acceleration.IsZero() {
+		t.Errorf("Expected evasive acceleration, got %v", s.Acceleration)
+	}
+}
+
+func TestEvadeOnSensors(t *testing.T) {
+	s := &Ship{Range:10, Velocity:Vector{X:1}}
+	m := &Missile{Ship:Ship{Position:Vector{Y:100}, Velocity:Vector{Y:-50}},
+		Target:s}
+	w := &World{Ships:[]*Ship{s}, Missiles:[]*Missile{m}}
+	c := &EvasionController{Ship:s, World:w, Acceleration:10}
+	if c.Redirect(); !s.Acceleration.IsZero() {
+		t.Errorf("Evaded an undetected missile: %v", s.Acceleration)
+	}
+	s.Range = 200
+	if c.Redirect(); s.Acceleration.IsZero() {
+		t.Error("Expected to evade the missile once seen.")
+	}
+}
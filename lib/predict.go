@@ -0,0 +1,89 @@
+// Forward simulation of ships on copies of the world.
+
+package lib
+
+// A Controller that steers relative to other ships implements Rebinder so
+// that it can be copied onto clones of those ships. Ships whose controller
+// does not are predicted to hold their current acceleration.
+type Rebinder interface {
+	Rebind(clones map[*Ship]*Ship) Controller
+}
+
+// Sampled future paths, keyed by the live ships they belong to.
+type Prediction struct {
+	Times []float64
+	Paths map[*Ship][]Vector
+	Events []Event
+}
+
+// Copy s so that flying the copy leaves s untouched.
+func (s *Ship) clone() *Ship {
+	c := *s
+	c.c = nil
+	c.Weapons = nil
+	for _, w := range s.Weapons {
+		weapon := *w
+		c.Weapons = append(c.Weapons, &weapon)
+	}
+	// Predictions stay within the system.
+	if s.Drive != nil {
+		d := *s.Drive
+		d.Destination = nil
+		c.Drive = &d
+	}
+	return &c
+}
+
+// Clone the given ships into a scratch world sharing w's bodies, returning
+// the world and the map from live ships to their clones.
+func (w *World) fork(ships []*Ship) (*World, map[*Ship]*Ship) {
+	if ships == nil { ships = w.Ships }
+	clones := make(map[*Ship]*Ship)
+	f := &World{Time: w.Time, Bodies: w.Bodies, CellSize: w.CellSize}
+	for _, s := range ships {
+		clones[s] = s.clone()
+		f.Ships = append(f.Ships, clones[s])
+	}
+	for _, s := range ships {
+		if r, ok := s.c.(Rebinder); ok { clones[s].c = r.Rebind(clones) }
+	}
+	return f, clones
+}
+
+// Fly copies of the given ships (every ship when nil) for duration seconds
+// in steps of dt, sampling their positions every interval seconds. The live
+// world is not modified.
+func (w *World) Predict(ships []*Ship, duration, dt, interval float64) *Prediction {
+	f, clones := w.fork(ships)
+	live := make(map[*Ship]*Ship)
+	for s, c := range clones { live[c] = s }
+	p := &Prediction{Paths: make(map[*Ship][]Vector)}
+	sample := func() {
+		p.Times = append(p.Times, f.Time)
+		for s, c := range clones { p.Paths[s] = append(p.Paths[s], c.Position) }
+	}
+	sample()
+	next := w.Time + interval
+	for steps := int(duration/dt + 0.5); steps > 0; steps-- {
+		for _, e := range f.Step(dt) {
+			e.Ship, e.Other = live[e.Ship], live[e.Other]
+			p.Events = append(p.Events, e)
+		}
+		if f.Time >= next - dt/2 {
+			sample()
+			next += interval
+		}
+	}
+	return p
+}
+
+// Return the predicted collisions involving s, earliest first.
+func (p *Prediction) Collisions(s *Ship) []Event {
+	var warnings []Event
+	for _, e := range p.Events {
+		if e.Kind == Collision && (e.Ship == s || e.Other == s) {
+			warnings = append(warnings, e)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,35 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestPredictLeavesWorldUntouched(t *testing.T) {
+	fixed := &Ship{}
+	gnat := &Ship{Position:Vector{X:400}, Velocity:Vector{Y:0.1}, Range:1000}
+	gnat.SetController(&ManeuverController{Ship:gnat, Target:fixed,
+		Maneuver:func(s, t *Ship) { s.Corkscrew(t, 40) }})
+	w := &World{Ships:[]*Ship{fixed, gnat}}
+	p := w.Predict(nil, 10, 0.01, 1)
+	if gnat.Position != (Vector{X:400}) || w.Time != 0 {
+		t.Errorf("Prediction moved the live ship to %v", gnat.Position)
+	}
+	if len(p.Times) != 11 || len(p.Paths[gnat]) != 11 {
+		t.Fatalf("Expected 11 samples, got %v", p.Times)
+	}
+	for i := 0; i < 1000; i++ { w.Step(0.01) }
+	if end := p.Paths[gnat][10]; !end.Equals(&gnat.Position) {
+		t.Errorf("Predicted %v, but the ship flew to %v", end, gnat.Position)
+	}
+}
+
+func TestPredictCollisions(t *testing.T) {
+	a := &Ship{Velocity:Vector{X:1}, Radius:1}
+	b := &Ship{Position:Vector{X:10}, Radius:1}
+	w := &World{Ships:[]*Ship{a, b}}
+	p := w.Predict(nil, 10, 1, 5)
+	c := p.Collisions(b)
+	if len(c) != 1 || c[0].Ship != a || c[0].Other != b || c[0].Time != 8 {
+		t.Errorf("Expected collision at 8s, got %v", c)
+	}
+}
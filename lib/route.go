@@ -0,0 +1,205 @@
+// Jump networks over the star catalog and A* route planning across them.
+
+package lib
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+)
+
+var ErrNoRoute = errors.New("no route to destination")
+
+// A connection from one star to another, by index into JumpGraph.Stars.
+type Lane struct {
+	To int
+	Length float64
+}
+
+type JumpGraph struct {
+	Stars []*Star
+	Lanes [][]Lane
+	// Length of the longest lane, in parsecs.
+	Range float64
+	index map[*Star]int
+}
+
+func newJumpGraph(stars []*Star) *JumpGraph {
+	g := &JumpGraph{Stars: stars, Lanes: make([][]Lane, len(stars)),
+		index: make(map[*Star]int)}
+	for i, s := range stars { g.index[s] = i }
+	return g
+}
+
+// Connect stars i and j both ways.
+func (g *JumpGraph) connect(i, j int) {
+	d := g.Stars[i].Distance(g.Stars[j])
+	g.Lanes[i] = append(g.Lanes[i], Lane{j, d})
+	g.Lanes[j] = append(g.Lanes[j], Lane{i, d})
+	if d > g.Range { g.Range = d }
+}
+
+// Return the grid cell of the given size holding s.
+func starCell(s *Star, size float64) cell {
+	return cell{int(math.Floor(s.X / size)), int(math.Floor(s.Y / size)),
+		int(math.Floor(s.Z / size))}
+}
+
+// Call f for every pair of stars no more than r parsecs apart, once each
+// with i < j. An r of zero or less, or infinite, takes every pair.
+func nearbyPairs(stars []*Star, r float64, f func(i, j int)) {
+	if r <= 0 || math.IsInf(r, 1) {
+		for i := range stars {
+			for j := i + 1; j < len(stars); j++ { f(i, j) }
+		}
+		return
+	}
+	grid := make(map[cell][]int)
+	for i, s := range stars {
+		k := starCell(s, r)
+		grid[k] = append(grid[k], i)
+	}
+	for i, s := range stars {
+		k := starCell(s, r)
+		for x := -1; x <= 1; x++ {
+			for y := -1; y <= 1; y++ {
+				for z := -1; z <= 1; z++ {
+					for _, j := range grid[cell{k[0] + x, k[1] + y, k[2] + z}] {
+						if i < j && s.Distance(stars[j]) <= r { f(i, j) }
+					}
+				}
+			}
+		}
+	}
+}
+
+// Build the graph joining every pair of stars within r parsecs, or every
+// pair at all when r is zero or less.
+func NewJumpGraph(stars []*Star, r float64) *JumpGraph {
+	g := newJumpGraph(stars)
+	nearbyPairs(stars, r, g.connect)
+	return g
+}
+
+type RouteCost int
+
+const (
+	FewestJumps RouteCost = iota
+	ShortestDistance
+	LeastFuel
+)
+
+// What a route should minimise, and the fuel constraints on it.
+type RoutePlan struct {
+	Cost RouteCost
+	FuelPerJump, FuelPerParsec float64
+	// Tank size and fuel aboard at the start; a zero Capacity ignores fuel.
+	Capacity, Fuel float64
+	// Report whether the tanks can be filled at a star; nil for nowhere.
+	Refuel func(*Star) bool
+}
+
+// Return a plan for routes the ship can fly with its drive and fuel.
+func (s *Ship) RoutePlan(cost RouteCost, capacity float64) *RoutePlan {
+	return &RoutePlan{Cost: cost, FuelPerJump: s.Drive.FuelPerJump,
+		FuelPerParsec: s.Drive.FuelPerParsec, Capacity: capacity, Fuel: s.Fuel}
+}
+
+type Route struct {
+	Stars []*Star
+	// Stops along the way where the tanks were filled.
+	Refuels []*Star
+	Jumps int
+	Distance, Fuel float64
+}
+
+// A partial route: how we got to a star and what we had left.
+type label struct {
+	star int
+	cost, f, fuel, distance, burned float64
+	jumps int
+	refuelled bool
+	prev *label
+}
+
+type labelQueue []*label
+
+func (q labelQueue) Len() int { return len(q) }
+func (q labelQueue) Less(i, j int) bool { return q[i].f < q[j].f }
+func (q labelQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *labelQueue) Push(x interface{}) { *q = append(*q, x.(*label)) }
+func (q *labelQueue) Pop() interface{} {
+	old := *q
+	l := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return l
+}
+
+func (p *RoutePlan) burn(d float64) float64 {
+	return p.FuelPerJump + p.FuelPerParsec*d
+}
+
+// Lower bound on the cost of covering d parsecs with lanes up to r long.
+func (p *RoutePlan) estimate(d, r float64) float64 {
+	if d == 0 { return 0 }
+	jumps := 1.0
+	if r > 0 { jumps = math.Max(1, math.Ceil(d/r - 1e-9)) }
+	switch p.Cost {
+	case FewestJumps: return jumps
+	case ShortestDistance: return d
+	}
+	return p.FuelPerJump*jumps + p.FuelPerParsec*d
+}
+
+// Find the cheapest route from one star to another with A*. When fuel is
+// limited the search tracks fuel left alongside cost, keeping any partial
+// route not beaten on both, so that detours to refuel are found.
+func (g *JumpGraph) Route(from, to *Star, p *RoutePlan) (*Route, error) {
+	start, ok1 := g.index[from]
+	goal, ok2 := g.index[to]
+	if !ok1 || !ok2 { return nil, ErrNoRoute }
+	q := &labelQueue{{star: start, fuel: p.Fuel,
+		f: p.estimate(from.Distance(to), g.Range)}}
+	settled := make([][]*label, len(g.Stars))
+	for q.Len() > 0 {
+		l := heap.Pop(q).(*label)
+		if l.star == goal { return l.route(g), nil }
+		dominated := false
+		for _, o := range settled[l.star] {
+			if o.cost <= l.cost && (p.Capacity == 0 || o.fuel >= l.fuel) {
+				dominated = true
+				break
+			}
+		}
+		if dominated { continue }
+		settled[l.star] = append(settled[l.star], l)
+		for _, lane := range g.Lanes[l.star] {
+			next := &label{star: lane.To, prev: l, jumps: l.jumps + 1,
+				distance: l.distance + lane.Length,
+				burned: l.burned + p.burn(lane.Length)}
+			if p.Capacity > 0 {
+				if next.fuel = l.fuel - p.burn(lane.Length); next.fuel < 0 { continue }
+				if p.Refuel != nil && lane.To != goal && p.Refuel(g.Stars[lane.To]) {
+					next.fuel, next.refuelled = p.Capacity, true
+				}
+			}
+			switch p.Cost {
+			case FewestJumps: next.cost = float64(next.jumps)
+			case ShortestDistance: next.cost = next.distance
+			case LeastFuel: next.cost = next.burned
+			}
+			next.f = next.cost + p.estimate(g.Stars[lane.To].Distance(to), g.Range)
+			heap.Push(q, next)
+		}
+	}
+	return nil, ErrNoRoute
+}
+
+func (l *label) route(g *JumpGraph) *Route {
+	r := &Route{Jumps: l.jumps, Distance: l.distance, Fuel: l.burned}
+	for ; l != nil; l = l.prev {
+		r.Stars = append([]*Star{g.Stars[l.star]}, r.Stars...)
+		if l.refuelled { r.Refuels = append([]*Star{g.Stars[l.star]}, r.Refuels...) }
+	}
+	return r
+}
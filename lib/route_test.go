@@ -0,0 +1,88 @@
+package lib
+
+import (
+	"testing"
+)
+
+// Stars strung out along the X axis at the given positions.
+func starLine(xs ...float64) []*Star {
+	var stars []*Star
+	for i, x := range xs {
+		stars = append(stars, &Star{Id:i, X:x})
+	}
+	return stars
+}
+
+func TestJumpGraph(t *testing.T) {
+	stars := starLine(0, 1, 2, 10)
+	g := NewJumpGraph(stars, 1.5)
+	if len(g.Lanes[0]) != 1 || len(g.Lanes[1]) != 2 || len(g.Lanes[3]) != 0 {
+		t.Errorf("Unexpected lanes %v", g.Lanes)
+	}
+	if g.Range != 1 {
+		t.Errorf("Expected longest lane of 1pc, got %v", g.Range)
+	}
+	if _, err := g.Route(stars[0], stars[3], &RoutePlan{}); err != ErrNoRoute {
+		t.Errorf("Expected %v, got %v", ErrNoRoute, err)
+	}
+	// No range joins every pair.
+	if g = NewJumpGraph(stars, 0); len(g.Lanes[3]) != 3 || g.Range != 10 {
+		t.Errorf("Expected every pair joined, got %v", g.Lanes)
+	}
+}
+
+func TestRouteCosts(t *testing.T) {
+	stars := starLine(0, 1, 2, 3, 4, 5)
+	g := NewJumpGraph(stars, 2.5)
+	r, err := g.Route(stars[0], stars[5], &RoutePlan{Cost:FewestJumps})
+	if err != nil || r.Jumps != 3 || r.Distance != 5 {
+		t.Errorf("Expected 3 jumps over 5pc, got %v (%v)", r, err)
+	}
+	plan := &RoutePlan{Cost:ShortestDistance, FuelPerJump:1, FuelPerParsec:1}
+	r, err = g.Route(stars[0], stars[5], plan)
+	if err != nil || r.Distance != 5 || r.Stars[0] != stars[0] ||
+		r.Stars[len(r.Stars)-1] != stars[5] {
+		t.Errorf("Expected a 5pc route from first to last star, got %v (%v)",
+			r, err)
+	}
+	plan.Cost = LeastFuel
+	if r, err = g.Route(stars[0], stars[5], plan); err != nil || r.Fuel != 8 {
+		t.Errorf("Expected the 3 jump route to burn 8 fuel, got %v (%v)", r, err)
+	}
+}
+
+func TestRouteRefuelling(t *testing.T) {
+	stars := starLine(0, 2, 4, 6, 8)
+	// A fuel depot off to the side of the third star.
+	stars = append(stars, &Star{Id:5, X:4, Y:1.5})
+	g := NewJumpGraph(stars, 2.5)
+	plan := &RoutePlan{Cost:ShortestDistance, FuelPerParsec:1, Capacity:5,
+		Fuel:5}
+	if _, err := g.Route(stars[0], stars[4], plan); err != ErrNoRoute {
+		t.Errorf("Expected to run dry without refuelling, got %v", err)
+	}
+	plan.Refuel = func(s *Star) bool { return s.Id == 5 }
+	r, err := g.Route(stars[0], stars[4], plan)
+	if err != nil {
+		t.Fatalf("Expected a route via the depot, got %v", err)
+	}
+	if len(r.Refuels) != 1 || r.Refuels[0] != stars[5] || r.Jumps != 4 {
+		t.Errorf("Expected to detour to refuel at %v, got %v", stars[5], r.Stars)
+	}
+}
+
+func TestRouteAcrossCatalog(t *testing.T) {
+	stars, err := readFromFile("HabHYG.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewJumpGraph(stars, 5)
+	sol, far := stars[0], stars[500]
+	r, err := g.Route(sol, far, &RoutePlan{Cost:ShortestDistance})
+	if err != nil {
+		t.Fatalf("No route from %v to %v: %v", sol.Name, far.Name, err)
+	}
+	if r.Distance < sol.Distance(far) || r.Jumps < int(sol.Distance(far) / 5) {
+		t.Errorf("Route %v beats the straight line", r)
+	}
+}
@@ -0,0 +1,66 @@
+// Sensors: what ships can see of each other.
+
+package lib
+
+import (
+	"math/rand"
+)
+
+// Acceleration at which a ship's signature doubles.
+const signatureThrust = 10.0
+
+// A sensor return. Ship is the ground truth, kept for identification and
+// scoring; controllers should steer by Position and Velocity alone.
+type Contact struct {
+	Time float64
+	Position Vector
+	Velocity Vector
+	Ship *Ship
+}
+
+// Return how conspicuous the ship is: one while coasting, growing as it
+// burns harder.
+func (s *Ship) Signature() float64 {
+	return 1 + s.Acceleration.Length() / signatureThrust
+}
+
+// Report whether s's sensors pick up t. A ship under heavy thrust is seen
+// from proportionally further away.
+func (s *Ship) Detects(t *Ship) bool {
+	return s.Distance(t) - t.Radius <= s.SensorRange() * t.Signature()
+}
+
+// Return s's reading of t, if it is detected. Readings are perturbed by
+// the sensor's noise, which grows with range, when r is not nil.
+func (s *Ship) Sense(t *Ship, r *rand.Rand) (Contact, bool) {
+	if !s.Detects(t) { return Contact{}, false }
+	c := Contact{Position: t.Position, Velocity: t.Velocity, Ship: t}
+	if r != nil && s.SensorNoise > 0 {
+		sigma := s.SensorNoise * s.Distance(t)
+		c.Position.PlusInPlace(&Vector{r.NormFloat64() * sigma,
+			r.NormFloat64() * sigma, r.NormFloat64() * sigma})
+		sigma = s.SensorNoise * s.Velocity.Distance(&t.Velocity)
+		c.Velocity.PlusInPlace(&Vector{r.NormFloat64() * sigma,
+			r.NormFloat64() * sigma, r.NormFloat64() * sigma})
+	}
+	return c, true
+}
+
+// Return a stand-in ship where the contact appears to be, for use as the
+// target of maneuvers.
+func (c *Contact) Estimate() *Ship {
+	return &Ship{Position: c.Position, Velocity: c.Velocity}
+}
+
+// Return everything s's sensors currently pick up.
+func (w *World) Scan(s *Ship) []Contact {
+	var contacts []Contact
+	for _, t := range w.Ships {
+		if t == s { continue }
+		if c, ok := s.Sense(t, w.Rand); ok {
+			c.Time = w.Time
+			contacts = append(contacts, c)
+		}
+	}
+	return contacts
+}
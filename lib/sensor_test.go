@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDetects(t *testing.T) {
+	s := &Ship{Range:100}
+	t1 := &Ship{Position:Vector{X:150}, Radius:5}
+	if s.Detects(t1) {
+		t.Error("Coasting ship detected beyond sensor range.")
+	}
+	t1.Acceleration.Y = 5
+	if !s.Detects(t1) {
+		t.Error("Ship under thrust should be detected at 1.5x range.")
+	}
+	s.Systems[Sensors] = 0.5
+	if s.Detects(t1) {
+		t.Error("Damaged sensors should not reach the target.")
+	}
+}
+
+func TestScanNoise(t *testing.T) {
+	s := &Ship{Range:1000, SensorNoise:0.01}
+	far := &Ship{Position:Vector{X:500}}
+	hidden := &Ship{Position:Vector{X:5000}}
+	w := &World{Ships:[]*Ship{s, far, hidden}}
+	if c := w.Scan(s); len(c) != 1 || c[0].Position != far.Position {
+		t.Errorf("Expected an exact contact on %v, got %v", far, c)
+	}
+	w.Rand = rand.New(rand.NewSource(1))
+	var mean Vector
+	for i := 0; i < 1000; i++ {
+		c := w.Scan(s)
+		if len(c) != 1 || c[0].Ship != far {
+			t.Fatalf("Expected a single contact, got %v", c)
+		}
+		mean.AddWithScaleInPlace(&c[0].Position, 0.001)
+	}
+	if d := mean.Distance(&far.Position); d == 0 || d > 1 {
+		t.Errorf("Noisy contacts average %v from the target", d)
+	}
+}
+
+func TestManeuverOnSensors(t *testing.T) {
+	s := &Ship{Range:10, Acceleration:Vector{X:1}}
+	target := &Ship{Position:Vector{X:100}}
+	c := &ManeuverController{Ship:s, Target:target,
+		Maneuver:func(s, t *Ship) { s.Circle(&t.Position, 2) }}
+	if c.Redirect(); !s.Acceleration.IsZero() {
+		t.Errorf("Ship steered toward an undetected target: %v", s.Acceleration)
+	}
+	s.Range = 200
+	if c.Redirect(); s.Acceleration != (Vector{X:2}) {
+		t.Errorf("Expected to steer at the contact, got %v", s.Acceleration)
+	}
+	// Noisy sensors put the contact off the target's line.
+	s.SensorNoise, c.Rand = 0.1, rand.New(rand.NewSource(1))
+	if c.Redirect(); s.Acceleration.Y == 0 || !fequal(s.Acceleration.Length(), 2) {
+		t.Errorf("Expected to steer at a noisy contact, got %v", s.Acceleration)
+	}
+}
+
+func TestFleeOnSensors(t *testing.T) {
+	s := &Ship{Range:10}
+	threat := &Ship{Position:Vector{X:100}}
+	f := &FleeController{s:s, a:2, from:threat}
+	if f.Redirect(); !s.Acceleration.IsZero() {
+		t.Errorf("Fled an undetected ship: %v", s.Acceleration)
+	}
+	s.Range = 200
+	if f.Redirect(); s.Acceleration != (Vector{X:-2}) {
+		t.Errorf("Expected to flee the contact, got %v", s.Acceleration)
+	}
+}
@@ -1,6 +1,7 @@
 package lib
 
 import (
+	"math"
 	"math/rand"
 )
 
@@ -8,6 +9,29 @@ type Ship struct {
 	Position Vector
 	Velocity Vector
 	Acceleration Vector
+	Radius float64
+	// Distance from the hull at which other objects are reported in range,
+	// and at which a coasting ship can be detected.
+	Range float64
+	// Sensor error per unit of distance to the target; zero for perfect.
+	SensorNoise float64
+	// Acceleration with the engines intact and the hold empty; zero for no
+	// limit.
+	MaxAcceleration float64
+	// Tonnes, unladen.
+	Mass float64
+	Hold Hold
+	Credits float64
+	Crew int
+	Weapons []*Weapon
+	// Hull points and damage taken; a ship without Hull is indestructible.
+	Hull, HullDamage float64
+	Shields [Facings]Shield
+	// Damage to each subsystem, from 0 (intact) to 1 (destroyed).
+	Systems [Subsystems]float64
+	Faction *Faction
+	Drive *JumpDrive
+	Fuel float64
 	c Controller
 }
 
@@ -31,6 +55,11 @@ type Controller interface {
 	Redirect()
 }
 
+// Hand steering of the ship to c, which is consulted every World step.
+func (s *Ship) SetController(c Controller) {
+	s.c = c
+}
+
 // Adopt the maximum acceleration away from the given point.
 func (s *Ship) Flee(p *Vector, a float64) {
 	// Since acceleration is overwritten, use it as "scratch" space.
@@ -43,14 +72,68 @@ func (s *Ship) Flee(p *Vector, a float64) {
 	}
 }
 
+// Flees the point p or, when from is set, where s senses that ship to be,
+// blurred by s's SensorNoise when r is set. The ship coasts while it cannot
+// see what it flees.
 type FleeController struct {
 	s *Ship
 	p *Vector
 	a float64
+	from *Ship
+	r *rand.Rand
 }
 
 func (f *FleeController) Redirect() {
-	f.s.Flee(f.p, f.a)
+	if f.from == nil {
+		f.s.Flee(f.p, f.a)
+	} else if c, ok := f.s.Sense(f.from, f.r); ok {
+		f.s.Flee(&c.Position, f.a)
+	} else {
+		f.s.Acceleration = Vector{}
+	}
+}
+
+func (f *FleeController) Rebind(clones map[*Ship]*Ship) Controller {
+	g := *f
+	for o, c := range clones {
+		if f.s == o { g.s = c }
+		if f.p == &o.Position { g.p = &c.Position }
+		if f.from == o { g.from = c }
+	}
+	g.r = nil
+	return &g
+}
+
+// Steer Ship relative to Target every tick with one of the maneuvers, e.g.
+// func(s, t *Ship) { s.Corkscrew(t, 40) }. The maneuver sees only what Ship
+// detects of Target, blurred by its SensorNoise when Rand is set, and the
+// ship coasts when it loses contact. With Exact set it sees Target as it
+// is, as when flying a ship's own simulation.
+type ManeuverController struct {
+	Ship, Target *Ship
+	Maneuver func(s, t *Ship)
+	Exact bool
+	// Source of sensor noise, usually the World's; nil for perfect sensors.
+	Rand *rand.Rand
+}
+
+func (m *ManeuverController) Redirect() {
+	if m.Exact {
+		m.Maneuver(m.Ship, m.Target)
+	} else if c, ok := m.Ship.Sense(m.Target, m.Rand); ok {
+		m.Maneuver(m.Ship, c.Estimate())
+	} else {
+		m.Ship.Acceleration = Vector{}
+	}
+}
+
+func (m *ManeuverController) Rebind(clones map[*Ship]*Ship) Controller {
+	n := *m
+	if c, ok := clones[m.Ship]; ok { n.Ship = c }
+	if c, ok := clones[m.Target]; ok { n.Target = c }
+	// Predictions must not draw on the live world's noise.
+	n.Rand = nil
+	return &n
 }
 
 // Return the perpendicular to u that lies nearest v.
@@ -105,3 +188,20 @@ func (s *Ship) MaintainDistance(t *Ship, a float64, d float64) {
 		position.Unit().Times(beta))
 	s.Acceleration.ScaleToInPlace(a)
 }
+
+// Pursue the target, as nav's Approach does: cancel the relative velocity
+// across the line of sight and put the remaining thrust into closing.
+func (s *Ship) Approach(t *Ship, a float64) {
+	toward := t.Position.Minus(&s.Position)
+	if toward.IsZero() {
+		s.Acceleration = Vector{}
+		return
+	}
+	lateral := s.Velocity.Minus(&t.Velocity).Reject(toward)
+	if l := lateral.Length(); l >= a {
+		s.Acceleration = *lateral.ScaleTo(-a)
+	} else {
+		s.Acceleration = *toward.ScaleTo(math.Sqrt(a*a - l*l))
+		s.Acceleration.MinusInPlace(lateral)
+	}
+}
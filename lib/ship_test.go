@@ -9,7 +9,7 @@ func TestMove(t *testing.T) {
 	s, p := &Ship{}, Vector{}
 	s.Move(1)
 	if s.Position != p {
-		t.Error("Ship with zero velocity moved to %v.", p)
+		t.Errorf("Ship with zero velocity moved to %v.", s.Position)
 	}
 	s.Velocity.Y = 2.0
 	p.Y = 6.0
@@ -120,3 +120,19 @@ func TestMaintainDistance(t *testing.T) {
 		}
 	}
 }
+
+func TestApproach(t *testing.T) {
+	p, s := &Ship{Position:Vector{X:10}}, &Ship{Velocity:Vector{Y:3}}
+	s.Approach(p, 5)
+	acc := &Vector{4, -3, 0}
+	if !s.Acceleration.Equals(acc) {
+		t.Errorf("Ship should approach at %v; instead going at %v.",
+			acc, s.Acceleration)
+	}
+	s.Approach(p, 2)
+	acc = &Vector{0, -2, 0}
+	if !s.Acceleration.Equals(acc) {
+		t.Errorf("Ship should approach at %v; instead going at %v.",
+			acc, s.Acceleration)
+	}
+}
@@ -2,6 +2,7 @@ package lib
 
 import (
 	"encoding/csv"
+	"math"
 	"os"
 	"strconv"
 	"io"
@@ -15,6 +16,20 @@ type Star struct {
 
 func (s *Star) Position() (x, y, z float64) { return s.X, s.Y, s.Z }
 
+// Distance between stars in parsecs.
+func (s *Star) Distance(t *Star) float64 {
+	x, y, z := s.X - t.X, s.Y - t.Y, s.Z - t.Z
+	return math.Sqrt(x*x + y*y + z*z)
+}
+
+// Return the letter of the star's spectral class, e.g. 'G' for Sol.
+func spectralType(s *Star) byte {
+	for i := 0; i < len(s.Class); i++ {
+		if c := s.Class[i]; c >= 'A' && c <= 'Z' { return c }
+	}
+	return 'G'
+}
+
 func atof(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
 
 func newStar(s []string) (*Star, error) {
@@ -44,6 +59,9 @@ func readFromCSV(reader io.Reader) ([]*Star, error) {
 	return nil, err
 }
 
+// Read the habitable stars from a HabHYG catalog file.
+func LoadStars(f string) ([]*Star, error) { return readFromFile(f) }
+
 func readFromFile(f string) ([]*Star, error) {
 	reader, err := os.Open(f)
 	if err != nil { return nil, err }
@@ -0,0 +1,85 @@
+// Composable steering: behaviors propose accelerations and are combined.
+
+package lib
+
+// A steering behavior proposes an acceleration for s without applying it.
+type Behavior interface {
+	Steer(s *Ship) Vector
+}
+
+// Adapt one of the Ship maneuvers, which set Acceleration directly, into a
+// behavior, e.g. Maneuver(func(s *Ship) { s.Circle(&p, 10) }).
+type Maneuver func(s *Ship)
+
+func (m Maneuver) Steer(s *Ship) Vector {
+	saved := s.Acceleration
+	m(s)
+	a := s.Acceleration
+	s.Acceleration = saved
+	return a
+}
+
+// Shorten v to at most l.
+func truncate(v Vector, l float64) Vector {
+	if v.Length() <= l { return v }
+	return *v.ScaleTo(l)
+}
+
+type Weighted struct {
+	Behavior Behavior
+	Weight float64
+}
+
+// The weighted sum of its behaviors, truncated to the ship's thrust. Every
+// behavior gets a say, so opposing ones can cancel out.
+type Blend []Weighted
+
+func (b Blend) Steer(s *Ship) Vector {
+	var sum Vector
+	for _, w := range b {
+		a := w.Behavior.Steer(s)
+		sum.AddWithScaleInPlace(&a, w.Weight)
+	}
+	return truncate(sum, s.Thrust())
+}
+
+// Its behaviors in order of importance, each given as much of the ship's
+// thrust as those before it leave over. A behavior with nothing to do
+// proposes no acceleration and leaves the thrust to the rest.
+type Priority []Behavior
+
+func (p Priority) Steer(s *Ship) Vector {
+	var sum Vector
+	left := s.Thrust()
+	for _, b := range p {
+		a := b.Steer(s)
+		l := a.Length()
+		if l == 0 { continue }
+		if l >= left {
+			a = *a.ScaleTo(left)
+			sum.PlusInPlace(&a)
+			break
+		}
+		sum.PlusInPlace(&a)
+		left -= l
+	}
+	return sum
+}
+
+// Steer the ship every tick with a behavior.
+type SteeringController struct {
+	Ship *Ship
+	Behavior Behavior
+}
+
+// Rebind moves the controller to the clone of Ship. Behaviors that refer
+// to other ships keep steering by the live ones.
+func (c *SteeringController) Rebind(clones map[*Ship]*Ship) Controller {
+	n := *c
+	if s, ok := clones[c.Ship]; ok { n.Ship = s }
+	return &n
+}
+
+func (c *SteeringController) Redirect() {
+	c.Ship.Acceleration = truncate(c.Behavior.Steer(c.Ship), c.Ship.Thrust())
+}
@@ -0,0 +1,68 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestManeuverBehavior(t *testing.T) {
+	s := &Ship{Acceleration:Vector{Z:1}}
+	flee := Maneuver(func(s *Ship) { s.Flee(&Vector{X:1}, 2) })
+	if a := flee.Steer(s); a != (Vector{X:-2}) || s.Acceleration != (Vector{Z:1}) {
+		t.Errorf("Expected to propose (-2, 0, 0) and leave the ship be, got %v", a)
+	}
+}
+
+func TestBlend(t *testing.T) {
+	s := &Ship{MaxAcceleration:1}
+	threat, target := Vector{X:10}, Vector{Y:10}
+	b := Blend{{Maneuver(func(s *Ship) { s.Flee(&threat, 1) }), 1},
+		{Maneuver(func(s *Ship) { s.Circle(&target, 1) }), 1}}
+	a := b.Steer(s)
+	if !fequal(a.Length(), 1) || a.X >= 0 || a.Y <= 0 || !fequal(a.X, -a.Y) {
+		t.Errorf("Expected to flee and close equally at full thrust, got %v", a)
+	}
+	s.MaxAcceleration = 0
+	if a := b.Steer(s); a != (Vector{-1, 1, 0}) {
+		t.Errorf("Expected an untruncated sum, got %v", a)
+	}
+}
+
+func TestPriority(t *testing.T) {
+	s := &Ship{MaxAcceleration:3}
+	threat, target := Vector{X:10}, Vector{Y:10}
+	p := Priority{Maneuver(func(s *Ship) { s.Flee(&threat, 2) }),
+		Maneuver(func(s *Ship) { s.Circle(&target, 2) })}
+	if a := p.Steer(s); a != (Vector{-2, 1, 0}) {
+		t.Errorf("Expected the circle to get the leftover thrust, got %v", a)
+	}
+	s.MaxAcceleration = 1
+	if a := p.Steer(s); a != (Vector{X:-1}) {
+		t.Errorf("Expected fleeing to take all the thrust, got %v", a)
+	}
+	// With nothing to flee, circling gets it all.
+	idle := Maneuver(func(s *Ship) { s.Acceleration = Vector{} })
+	p[0] = idle
+	if a := p.Steer(s); a != (Vector{Y:1}) {
+		t.Errorf("Expected to circle at full thrust, got %v", a)
+	}
+	s.SetController(&SteeringController{s, Blend{{idle, 1}, {p[1], 3}}})
+	w := &World{}
+	w.Add(s)
+	w.Step(1)
+	if s.Acceleration != (Vector{Y:1}) {
+		t.Errorf("Expected the controller to steer within thrust, got %v",
+			s.Acceleration)
+	}
+}
+
+func TestPredictSteering(t *testing.T) {
+	s := &Ship{MaxAcceleration:1}
+	s.SetController(&SteeringController{s,
+		Maneuver(func(s *Ship) { s.Acceleration = Vector{X:1} })})
+	w := &World{}
+	w.Add(s)
+	path := w.Predict(nil, 2, 1, 2).Paths[s]
+	if end := path[len(path) - 1]; end != (Vector{X:2}) {
+		t.Errorf("Expected the prediction to steer, got %v", end)
+	}
+}
@@ -0,0 +1,82 @@
+// Star systems: a catalog star with a world of ships flying around it.
+
+package lib
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Default distance from a star at which jumps arrive.
+const DefaultEdge = 1e5
+
+type System struct {
+	Star *Star
+	World *World
+	Planets []*Body
+	Market *Market
+	// Distance from the star at which arriving ships drop out of jump.
+	Edge float64
+}
+
+func NewSystem(star *Star) *System {
+	s := &System{Star: star, World: &World{}, Edge: DefaultEdge}
+	s.Planets = GeneratePlanets(star)
+	s.World.Bodies = append(s.World.Bodies, s.Planets...)
+	s.Market = NewMarket(star, s.Planets)
+	return s
+}
+
+// Advance the system's ships and market by dt seconds.
+func (s *System) Step(dt float64) []Event {
+	s.Market.Update(dt)
+	return s.World.Step(dt)
+}
+
+// Build a station for owner at the given position.
+func (s *System) AddStation(name string, owner *Faction, p Vector) *Body {
+	b := &Body{Name: name, Kind: Station, Position: p, Radius: 1, Owner: owner}
+	s.World.Bodies = append(s.World.Bodies, b)
+	return b
+}
+
+// Return where ships trade with the system's market: its first station or,
+// failing that, its innermost planet; nil if it has neither.
+func (s *System) Port() *Body {
+	for _, b := range s.World.Bodies {
+		if b.Kind == Station { return b }
+	}
+	if len(s.Planets) > 0 { return s.Planets[0] }
+	return nil
+}
+
+// Return the planets orbiting star. They are generated from the star's Id
+// and spectral class, so a star always has the same planets.
+func GeneratePlanets(star *Star) []*Body {
+	r := rand.New(rand.NewSource(int64(star.Id)))
+	n := 2 + r.Intn(6)
+	// Kinds by distance from the star: inner, temperate and outer orbits.
+	inner, outer := 1, 3
+	switch spectralType(star) {
+	case 'O', 'B', 'A': inner, outer = 2, 4
+	case 'M': inner, outer = 0, 2
+	}
+	var planets []*Body
+	for i := 0; i < n; i++ {
+		p := &Body{Name: fmt.Sprintf("%v %c", star.Name, 'b' + i)}
+		switch {
+		case i < inner: p.Kind = Rocky
+		case i <= inner && r.Intn(2) == 0: p.Kind = Ocean
+		case i < outer: p.Kind = Rocky
+		case r.Intn(3) == 0: p.Kind = Ice
+		default: p.Kind = GasGiant
+		}
+		p.Radius = 10 + r.Float64() * 40
+		if p.Kind == GasGiant { p.Radius *= 4 }
+		orbit, angle := 2000 * math.Pow(1.6, float64(i)), r.Float64() * 2 * math.Pi
+		p.Position = Vector{orbit * math.Cos(angle), orbit * math.Sin(angle), 0}
+		planets = append(planets, p)
+	}
+	return planets
+}
@@ -0,0 +1,118 @@
+// Tactical AI: choosing how to fight an enemy by simulating the options.
+
+package lib
+
+import (
+	"math"
+)
+
+// A way of fighting: one of the ship maneuvers against the enemy.
+type Tactic struct {
+	Name string
+	Maneuver func(s, t *Ship)
+}
+
+// Return the stock tactics for a ship with acceleration a whose weapons
+// reach d: the evasive maneuvers and an attack run.
+func Tactics(a, d float64) []Tactic {
+	return []Tactic{
+		{"spiral away", func(s, t *Ship) { s.SpiralAway(t, a) }},
+		{"corkscrew", func(s, t *Ship) { s.Corkscrew(t, a) }},
+		{"maintain distance", func(s, t *Ship) { s.MaintainDistance(t, a, d) }},
+		{"flee", func(s, t *Ship) { s.Flee(&t.Position, a) }},
+		{"attack run", func(s, t *Ship) { s.Approach(t, a) }},
+	}
+}
+
+// Steer s against t with the tactic, returning false and coasting if it
+// has no answer here, as when MaintainDistance has no tangential velocity
+// to work with.
+func (tactic *Tactic) fly(s, t *Ship) bool {
+	tactic.Maneuver(s, t)
+	a := &s.Acceleration
+	if math.IsNaN(a.X + a.Y + a.Z) || math.IsInf(a.X + a.Y + a.Z, 0) {
+		s.Acceleration = Vector{}
+		return false
+	}
+	return true
+}
+
+// Fire every weapon s has ready at t.
+func (w *World) fireAll(s, t *Ship) {
+	for _, weapon := range s.Weapons { w.Fire(s, weapon, t) }
+}
+
+// Fights Enemy, re-planning every Replan seconds by flying each of its
+// tactics for Horizon seconds on a copy of the world, with the enemy where
+// Ship's sensors put it, both sides firing whenever they can, and picking
+// the best. It coasts while the enemy is unseen. A tactic scores the hull damage
+// it deals, weighted by Aggression, less the damage taken; one that loses
+// the ship, or that the maneuver cannot fly, scores worst of all.
+type Tactician struct {
+	Ship, Enemy *Ship
+	World *World
+	Tactics []Tactic
+	Horizon, Step, Replan float64
+	Aggression float64
+	// The tactic being flown and when it was chosen.
+	Choice *Tactic
+	chosen float64
+	// The latest sighting of the enemy.
+	contact Contact
+}
+
+// Return the score of flying tactic for the horizon.
+func (c *Tactician) evaluate(tactic *Tactic) float64 {
+	f, clones := c.World.fork([]*Ship{c.Ship, c.Enemy})
+	s, e := clones[c.Ship], clones[c.Enemy]
+	e.Position, e.Velocity = c.contact.Position, c.contact.Velocity
+	failed := false
+	s.SetController(&ManeuverController{Ship: s, Target: e, Exact: true,
+		Maneuver: func(s, t *Ship) { failed = !tactic.fly(s, t) || failed }})
+	dealt, taken := 0.0, 0.0
+	for steps := int(c.Horizon/c.Step + 0.5); steps > 0; steps-- {
+		f.fireAll(s, e)
+		f.fireAll(e, s)
+		events := f.Step(c.Step)
+		if failed { return math.Inf(-1) }
+		for _, ev := range events {
+			switch {
+			case ev.Kind == Damaged && ev.Ship == e: dealt += ev.Damage
+			case ev.Kind == Damaged && ev.Ship == s: taken += ev.Damage
+			case ev.Kind == Destroyed && ev.Ship == s: return math.Inf(-1)
+			case ev.Kind == Destroyed && ev.Ship == e:
+				// Nothing left to fight.
+				return c.Aggression * dealt - taken
+			}
+		}
+	}
+	return c.Aggression * dealt - taken
+}
+
+// Choose the best tactic for the situation as it stands.
+func (c *Tactician) plan() {
+	best := math.Inf(-1)
+	c.Choice = nil
+	for i := range c.Tactics {
+		if score := c.evaluate(&c.Tactics[i]); c.Choice == nil || score > best {
+			c.Choice, best = &c.Tactics[i], score
+		}
+	}
+	c.chosen = c.World.Time
+}
+
+func (c *Tactician) Redirect() {
+	if c.Enemy.Destroyed() {
+		c.Ship.Acceleration = Vector{}
+		return
+	}
+	contact, ok := c.Ship.Sense(c.Enemy, c.World.Rand)
+	if !ok {
+		c.Ship.Acceleration = Vector{}
+		return
+	}
+	c.contact = contact
+	if c.Choice == nil || c.World.Time - c.chosen >= c.Replan { c.plan() }
+	c.Choice.fly(c.Ship, contact.Estimate())
+	c.World.fireAll(c.Ship, c.Enemy)
+}
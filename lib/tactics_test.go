@@ -0,0 +1,56 @@
+package lib
+
+import (
+	"testing"
+)
+
+func tacticalWorld() (w *World, s, enemy *Ship) {
+	s = &Ship{Hull:10, MaxAcceleration:2, Range:1000}
+	enemy = &Ship{Position:Vector{X:40}, Hull:10, MaxAcceleration:1,
+		Range:1000}
+	w = &World{}
+	w.Add(s)
+	w.Add(enemy)
+	return
+}
+
+func TestTacticianEvades(t *testing.T) {
+	w, s, enemy := tacticalWorld()
+	enemy.Weapons = []*Weapon{{Kind:Beam, Range:20, Damage:1, RateOfFire:1}}
+	enemy.SetController(&ManeuverController{Ship:enemy, Target:s,
+		Maneuver:func(s, t *Ship) { s.Approach(t, 1) }})
+	c := &Tactician{Ship:s, Enemy:enemy, World:w, Tactics:Tactics(2, 20),
+		Horizon:20, Step:0.5, Replan:5, Aggression:1}
+	s.SetController(c)
+	w.Step(0.5)
+	if c.Choice == nil || c.Choice.Name == "attack run" {
+		t.Fatalf("Expected an evasive choice, got %v", c.Choice)
+	}
+	for i := 0; i < 40; i++ { w.Step(0.5) }
+	if c.chosen != 20 { t.Errorf("Expected a re-plan at 20s, got %v", c.chosen) }
+	if s.HullDamage > 0 { t.Errorf("Expected to escape unhurt, took %v", s.HullDamage) }
+	if enemy.HullDamage != 0 { t.Error("Damage dealt without weapons.") }
+}
+
+func TestTacticianAttacks(t *testing.T) {
+	w, s, enemy := tacticalWorld()
+	s.Weapons = []*Weapon{{Kind:Beam, Range:20, Damage:1, RateOfFire:1}}
+	c := &Tactician{Ship:s, Enemy:enemy, World:w, Tactics:Tactics(2, 20),
+		Horizon:20, Step:0.5, Replan:5, Aggression:1}
+	s.SetController(c)
+	for i := 0; i < 40; i++ { w.Step(0.5) }
+	if name := c.Choice.Name; name == "flee" || name == "spiral away" {
+		t.Errorf("Expected to engage a helpless enemy, chose to %v", name)
+	}
+	if enemy.HullDamage == 0 { t.Error("Expected to damage the enemy.") }
+}
+
+func TestTacticianNeedsContact(t *testing.T) {
+	w, s, enemy := tacticalWorld()
+	s.Range, s.Acceleration = 10, Vector{X:1}
+	c := &Tactician{Ship:s, Enemy:enemy, World:w, Tactics:Tactics(2, 20),
+		Horizon:20, Step:0.5, Replan:5, Aggression:1}
+	if c.Redirect(); c.Choice != nil || !s.Acceleration.IsZero() {
+		t.Errorf("Fought an unseen enemy with %v", c.Choice)
+	}
+}
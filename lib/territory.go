@@ -0,0 +1,197 @@
+// Territory: how far each faction's influence reaches across the catalog.
+
+package lib
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+)
+
+// Weights of the sources of influence and how they spread.
+type InfluenceConfig struct {
+	// Influence of an owned system, of each station and of each ship.
+	System, Station, Ship float64
+	// Distance in parsecs at which influence has fallen to half.
+	Falloff float64
+	// Influence below this claims nothing.
+	Threshold float64
+	// A star is contested when the runner-up has at least this fraction of
+	// the leader's influence.
+	Contest float64
+}
+
+var DefaultInfluence = InfluenceConfig{System: 10, Station: 5, Ship: 1,
+	Falloff: 3, Threshold: 1, Contest: 0.8}
+
+// Who holds a star, by influence.
+type Territory struct {
+	Star *Star
+	Owner *Faction
+	Influence float64
+	// The strongest other faction at the star, if any.
+	Rival *Faction
+	Contested bool
+}
+
+// A lane between stars held by different factions.
+type Border struct {
+	From, To *Territory
+}
+
+// Each faction's influence at every star of the game's map.
+type InfluenceMap struct {
+	Stars []*Star
+	Factions []*Faction
+	// Influence[i][j] is that of Factions[j] at Stars[i].
+	Influence [][]float64
+	Config InfluenceConfig
+	lanes *JumpGraph
+}
+
+// Return the sources of influence in play: what each faction has at each
+// star, weighted by the config.
+func (g *Game) sources(c *InfluenceConfig) map[*Star]map[*Faction]float64 {
+	at := make(map[*Star]map[*Faction]float64)
+	add := func(star *Star, f *Faction, w float64) {
+		if f == nil || w == 0 { return }
+		if at[star] == nil { at[star] = make(map[*Faction]float64) }
+		at[star][f] += w
+	}
+	for _, star := range g.Stars { add(star, g.owners[star.Id], c.System) }
+	for _, s := range g.Systems {
+		for _, b := range s.World.Bodies {
+			if b.Kind == Station { add(s.Star, b.Owner, c.Station) }
+		}
+		for _, ship := range s.World.Ships {
+			if !ship.Destroyed() { add(s.Star, ship.Faction, c.Ship) }
+		}
+	}
+	return at
+}
+
+// Compute the influence of every faction over the map. Each source's
+// influence falls off with the square of distance beyond c.Falloff.
+func (g *Game) Influence(c InfluenceConfig) *InfluenceMap {
+	m := &InfluenceMap{Stars: g.Stars, Factions: g.Factions,
+		Influence: make([][]float64, len(g.Stars)), Config: c, lanes: g.Lanes}
+	column := make(map[*Faction]int)
+	for j, f := range g.Factions { column[f] = j }
+	for i := range m.Influence { m.Influence[i] = make([]float64, len(g.Factions)) }
+	for src, weights := range g.sources(&c) {
+		for i, star := range g.Stars {
+			d := src.Distance(star) / c.Falloff
+			for f, w := range weights {
+				// Factions outside the game have no column.
+				if j, ok := column[f]; ok { m.Influence[i][j] += w / (1 + d*d) }
+			}
+		}
+	}
+	return m
+}
+
+// Return who holds the i-th star.
+func (m *InfluenceMap) Territory(i int) Territory {
+	t := Territory{Star: m.Stars[i]}
+	var second float64
+	for j, x := range m.Influence[i] {
+		switch {
+		case x > t.Influence:
+			t.Rival, second = t.Owner, t.Influence
+			t.Owner, t.Influence = m.Factions[j], x
+		case x > second:
+			t.Rival, second = m.Factions[j], x
+		}
+	}
+	if t.Influence < m.Config.Threshold {
+		return Territory{Star: m.Stars[i], Influence: t.Influence}
+	}
+	if second < m.Config.Threshold { t.Rival = nil }
+	t.Contested = t.Rival != nil && second >= m.Config.Contest * t.Influence
+	return t
+}
+
+// Return who holds every star, in map order.
+func (m *InfluenceMap) Table() []Territory {
+	table := make([]Territory, len(m.Stars))
+	for i := range table { table[i] = m.Territory(i) }
+	return table
+}
+
+// Return the lanes joining stars held by different factions, where empires
+// meet. Lanes into unclaimed space are not borders.
+func (m *InfluenceMap) Borders() []Border {
+	table := m.Table()
+	var borders []Border
+	for i, lanes := range m.lanes.Lanes {
+		for _, l := range lanes {
+			a, b := &table[i], &table[l.To]
+			if i < l.To && a.Owner != nil && b.Owner != nil && a.Owner != b.Owner {
+				borders = append(borders, Border{a, b})
+			}
+		}
+	}
+	return borders
+}
+
+// Write the ownership table as CSV: star Id and name, owner, influence,
+// rival and whether the star is contested. Unclaimed stars are left out.
+func (m *InfluenceMap) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	name := func(f *Faction) string {
+		if f == nil { return "" }
+		return f.Name
+	}
+	err := out.Write([]string{"id", "star", "owner", "influence", "rival",
+		"contested"})
+	if err != nil { return err }
+	for _, t := range m.Table() {
+		if t.Owner == nil { continue }
+		err := out.Write([]string{fmt.Sprint(t.Star.Id), t.Star.Name,
+			name(t.Owner), fmt.Sprintf("%.3f", t.Influence), name(t.Rival),
+			fmt.Sprint(t.Contested)})
+		if err != nil { return err }
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// Colours for factions, in the order they joined the game.
+var factionColors = []color.RGBA{{220, 50, 50, 255}, {50, 100, 230, 255},
+	{50, 190, 80, 255}, {230, 190, 40, 255}, {170, 70, 200, 255},
+	{40, 200, 200, 255}, {240, 130, 30, 255}, {200, 110, 150, 255}}
+
+type byMagnitude []*Star
+
+func (s byMagnitude) Len() int { return len(s) }
+func (s byMagnitude) Less(i, j int) bool { return s[i].Magnitude > s[j].Magnitude }
+func (s byMagnitude) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// Draw the map looking down the Z axis, scale pixels to the parsec and
+// centred on Sol: each star in its owner's colour, contested stars in white
+// and unclaimed ones in grey.
+func (m *InfluenceMap) Render(size int, scale float64) *image.RGBA {
+	img := image.NewRGBA(image.Rect(-size, -size, size, size))
+	table := m.Table()
+	colors := make(map[*Star]color.RGBA)
+	for _, t := range table {
+		c := color.RGBA{90, 90, 90, 255}
+		for j, f := range m.Factions {
+			if t.Owner == f { c = factionColors[j % len(factionColors)] }
+		}
+		if t.Contested { c = color.RGBA{255, 255, 255, 255} }
+		colors[t.Star] = c
+	}
+	// Draw bright stars last, so they show where stars overlap.
+	stars := append([]*Star{}, m.Stars...)
+	sort.Sort(byMagnitude(stars))
+	for _, s := range stars {
+		x, y := int(math.Floor(s.X * scale)), int(math.Floor(s.Y * scale))
+		img.SetRGBA(x, y, colors[s])
+	}
+	return img
+}
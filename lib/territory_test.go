@@ -0,0 +1,71 @@
+package lib
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestInfluence(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0, 1, 2, 3, 4, 5, 6), 1.5))
+	empire, rebels := g.AddFaction("Empire"), g.AddFaction("Rebels")
+	g.Claim(empire, g.Stars[0])
+	g.Claim(rebels, g.Stars[6])
+	g.System(g.Stars[5]).AddStation("Base", rebels, Vector{})
+	c := DefaultInfluence
+	c.Threshold, c.Contest = 2, 0.5
+	m := g.Influence(c)
+	table := m.Table()
+	if table[0].Owner != empire || table[1].Owner != empire ||
+		table[6].Owner != rebels || table[5].Owner != rebels {
+		t.Errorf("Expected each faction to hold its own end, got %v", table)
+	}
+	if table[3].Owner == nil || !table[3].Contested {
+		t.Errorf("Expected the middle to be contested, got %v", table[3])
+	}
+	borders := m.Borders()
+	if len(borders) != 1 || borders[0].From.Owner != empire ||
+		borders[0].To.Owner != rebels {
+		t.Errorf("Expected a single border, got %v", borders)
+	}
+	// Ships tip the balance at the front.
+	for i := 0; i < 20; i++ {
+		s := &Ship{}
+		empire.Commission(s, "Home Fleet")
+		g.System(g.Stars[4]).World.Add(s)
+	}
+	if owner := g.Influence(c).Territory(5).Owner; owner != empire {
+		t.Errorf("Expected the fleet to take star 5, got %v", owner)
+	}
+	// Ships of factions outside the game have no influence.
+	empty := NewGame(NewJumpGraph(starLine(0), 1))
+	s := &Ship{}
+	NewFaction("Pirates").Commission(s, "Raiders")
+	empty.System(empty.Stars[0]).World.Add(s)
+	if owner := empty.Influence(c).Territory(0).Owner; owner != nil {
+		t.Errorf("Expected no owner without factions, got %v", owner)
+	}
+}
+
+func TestInfluenceExport(t *testing.T) {
+	g := NewGame(NewJumpGraph(starLine(0, 10), 1.5))
+	g.Claim(g.AddFaction("Empire"), g.Stars[0])
+	pirates := NewFaction("Pirates")
+	for i := 0; i < 20; i++ {
+		s := &Ship{}
+		pirates.Commission(s, "Raiders")
+		g.System(g.Stars[1]).World.Add(s)
+	}
+	m := g.Influence(DefaultInfluence)
+	var b bytes.Buffer
+	if err := m.WriteCSV(&b); err != nil { t.Fatal(err) }
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "Empire") {
+		t.Errorf("Expected one claimed star, got %q", lines)
+	}
+	img := m.Render(16, 1)
+	if img.RGBAAt(0, 0) != factionColors[0] {
+		t.Errorf("Expected the empire's colour at its home star, got %v",
+			img.RGBAAt(0, 0))
+	}
+}
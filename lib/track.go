@@ -0,0 +1,178 @@
+// Kalman filter tracking of sensor contacts.
+
+package lib
+
+import (
+	"math"
+	"sort"
+)
+
+// A target's estimated state under a constant acceleration model. Noise is
+// the same along every axis, so one 3x3 covariance over (position,
+// velocity, acceleration) serves for X, Y and Z alike.
+type Track struct {
+	Id int
+	Time float64
+	Position, Velocity, Acceleration Vector
+	P [3][3]float64
+	// Updates received, and consecutive updates missed.
+	Hits, Misses int
+}
+
+// Follows any number of contacts, associating each sensor return with the
+// nearest track.
+type Tracker struct {
+	// Standard deviation of contact positions.
+	MeasurementNoise float64
+	// Intensity of the random jerk the targets are assumed to pull.
+	ProcessNoise float64
+	// Contacts further than Gate standard deviations from every track
+	// start a new one.
+	Gate float64
+	// Hits before a track is confirmed, and misses before it is dropped.
+	Confirm, Drop int
+	Tracks []*Track
+	next int
+}
+
+func (t *Track) Confirmed(tr *Tracker) bool { return t.Hits >= tr.Confirm }
+
+// Return the per-axis covariance of position, velocity and acceleration.
+func (t *Track) Covariance() [3][3]float64 { return t.P }
+
+// Return a stand-in ship at the track's estimated state.
+func (t *Track) Estimate() *Ship {
+	return &Ship{Position: t.Position, Velocity: t.Velocity,
+		Acceleration: t.Acceleration}
+}
+
+// Advance the track's estimate to time.
+func (t *Track) predict(time, q float64) {
+	dt := time - t.Time
+	if dt <= 0 { return }
+	t.Time = time
+	t.Position.AddWithScaleInPlace(&t.Velocity, dt)
+	t.Position.AddWithScaleInPlace(&t.Acceleration, 0.5*dt*dt)
+	t.Velocity.AddWithScaleInPlace(&t.Acceleration, dt)
+	f := [3][3]float64{{1, dt, dt*dt/2}, {0, 1, dt}, {0, 0, 1}}
+	var fp, p [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ { fp[i][j] += f[i][k] * t.P[k][j] }
+		}
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ { p[i][j] += fp[i][k] * f[j][k] }
+		}
+	}
+	// Discrete white noise jerk.
+	d2, d3, d4, d5 := dt*dt, dt*dt*dt, dt*dt*dt*dt, dt*dt*dt*dt*dt
+	noise := [3][3]float64{{d5/20, d4/8, d3/6}, {d4/8, d3/3, d2/2},
+		{d3/6, d2/2, dt}}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ { p[i][j] += q * noise[i][j] }
+	}
+	t.P = p
+}
+
+// Fold a position measurement with variance r into the track.
+func (t *Track) update(z *Vector, r float64) {
+	s := t.P[0][0] + r
+	k := [3]float64{t.P[0][0] / s, t.P[1][0] / s, t.P[2][0] / s}
+	innovation := z.Minus(&t.Position)
+	t.Position.AddWithScaleInPlace(innovation, k[0])
+	t.Velocity.AddWithScaleInPlace(innovation, k[1])
+	t.Acceleration.AddWithScaleInPlace(innovation, k[2])
+	var p [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ { p[i][j] = t.P[i][j] - k[i]*t.P[0][j] }
+	}
+	t.P = p
+	t.Hits++
+	t.Misses = 0
+}
+
+type association struct {
+	track, contact int
+	d float64
+}
+
+type byDistance []association
+
+func (a byDistance) Len() int { return len(a) }
+func (a byDistance) Less(i, j int) bool { return a[i].d < a[j].d }
+func (a byDistance) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+
+// Bring every track up to time and fold in the contacts seen then, pairing
+// tracks and contacts nearest first. Contacts left over start tentative
+// tracks; tracks left over count a miss and are dropped after Drop of them,
+// or at once if never confirmed.
+func (tr *Tracker) Update(time float64, contacts []Contact) {
+	r := tr.MeasurementNoise * tr.MeasurementNoise
+	var pairs []association
+	for i, t := range tr.Tracks {
+		t.predict(time, tr.ProcessNoise)
+		sigma := math.Sqrt(t.P[0][0] + r)
+		for j := range contacts {
+			d := t.Position.Distance(&contacts[j].Position) / sigma
+			if d <= tr.Gate { pairs = append(pairs, association{i, j, d}) }
+		}
+	}
+	sort.Stable(byDistance(pairs))
+	tracked := make([]bool, len(tr.Tracks))
+	seen := make([]bool, len(contacts))
+	for _, a := range pairs {
+		if tracked[a.track] || seen[a.contact] { continue }
+		tracked[a.track], seen[a.contact] = true, true
+		tr.Tracks[a.track].update(&contacts[a.contact].Position, r)
+	}
+	live := tr.Tracks[:0]
+	for i, t := range tr.Tracks {
+		if !tracked[i] {
+			t.Misses++
+			if !t.Confirmed(tr) || t.Misses >= tr.Drop { continue }
+		}
+		live = append(live, t)
+	}
+	tr.Tracks = live
+	for j, c := range contacts {
+		if seen[j] { continue }
+		tr.next++
+		// Start from the reported velocity but trust only the position.
+		tr.Tracks = append(tr.Tracks, &Track{Id: tr.next, Time: time,
+			Position: c.Position, Velocity: c.Velocity, Hits: 1,
+			P: [3][3]float64{{r, 0, 0}, {0, 100 * r, 0}, {0, 0, 100 * r}}})
+	}
+}
+
+// Return the confirmed track nearest p, or nil.
+func (tr *Tracker) Nearest(p *Vector) *Track {
+	var best *Track
+	for _, t := range tr.Tracks {
+		if !t.Confirmed(tr) { continue }
+		if best == nil || p.SquaredDistance(&t.Position) <
+			p.SquaredDistance(&best.Position) {
+			best = t
+		}
+	}
+	return best
+}
+
+// Steer Ship against the nearest confirmed track built from what it sees in
+// World, coasting while nothing is being tracked.
+type TrackingController struct {
+	Ship *Ship
+	World *World
+	Tracker *Tracker
+	Maneuver func(s, t *Ship)
+}
+
+func (c *TrackingController) Redirect() {
+	c.Tracker.Update(c.World.Time, c.World.Scan(c.Ship))
+	if t := c.Tracker.Nearest(&c.Ship.Position); t != nil {
+		c.Maneuver(c.Ship, t.Estimate())
+	} else {
+		c.Ship.Acceleration = Vector{}
+	}
+}
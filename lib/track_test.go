@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTrackerConverges(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	tr := &Tracker{MeasurementNoise:2, ProcessNoise:0.01, Gate:5, Confirm:3,
+		Drop:3}
+	target := &Ship{Position:Vector{100, 0, 0}, Velocity:Vector{0, 10, 0},
+		Acceleration:Vector{0, 0, 1}}
+	for i := 0; i < 200; i++ {
+		z := target.Position
+		z.PlusInPlace(&Vector{r.NormFloat64() * 2, r.NormFloat64() * 2,
+			r.NormFloat64() * 2})
+		tr.Update(float64(i) * 0.1, []Contact{{Position:z}})
+		target.Move(0.1)
+	}
+	if len(tr.Tracks) != 1 {
+		t.Fatalf("Expected a single track, got %v", len(tr.Tracks))
+	}
+	tr.Update(20, nil)
+	track := tr.Tracks[0]
+	if d := track.Velocity.Distance(&target.Velocity); d > 1 {
+		t.Errorf("Velocity estimate %v is %v off %v", track.Velocity, d,
+			target.Velocity)
+	}
+	if d := track.Acceleration.Distance(&target.Acceleration); d > 0.5 {
+		t.Errorf("Acceleration estimate %v is %v off %v", track.Acceleration, d,
+			target.Acceleration)
+	}
+	if p := track.Covariance(); p[0][0] <= 0 || p[0][0] > 4 {
+		t.Errorf("Position variance %v should be below the measurement's", p[0][0])
+	}
+}
+
+func TestTrackerAssociation(t *testing.T) {
+	tr := &Tracker{MeasurementNoise:1, Gate:4, Confirm:2, Drop:2}
+	a, b := Vector{X:0}, Vector{X:50}
+	for i := 0; i < 5; i++ {
+		a.Y, b.Y = float64(i), float64(-i)
+		// Report the contacts in alternating order.
+		if i % 2 == 0 {
+			tr.Update(float64(i), []Contact{{Position:a}, {Position:b}})
+		} else {
+			tr.Update(float64(i), []Contact{{Position:b}, {Position:a}})
+		}
+	}
+	if len(tr.Tracks) != 2 || tr.Tracks[0].Id != 1 || tr.Tracks[1].Id != 2 {
+		t.Fatalf("Expected to keep the original two tracks, got %v", tr.Tracks)
+	}
+	if n := tr.Nearest(&Vector{X:40}); n != tr.Tracks[1] {
+		t.Errorf("Expected track 2 to be nearest, got %v", n)
+	}
+	// A stray return starts a tentative track which dies on its first miss.
+	a.Y, b.Y = 5, -5
+	tr.Update(5, []Contact{{Position:a}, {Position:b},
+		{Position:Vector{Z:1000}}})
+	if len(tr.Tracks) != 3 || tr.Nearest(&Vector{Z:1000}).Id == 3 {
+		t.Errorf("Expected an unconfirmed third track, got %v", tr.Tracks)
+	}
+	a.Y, b.Y = 6, -6
+	tr.Update(6, []Contact{{Position:a}, {Position:b}})
+	if len(tr.Tracks) != 2 {
+		t.Errorf("Expected tentative track to be dropped, got %v", tr.Tracks)
+	}
+	tr.Update(7, nil)
+	tr.Update(8, nil)
+	if len(tr.Tracks) != 0 {
+		t.Errorf("Expected lost tracks to be dropped, got %v", tr.Tracks)
+	}
+}
+
+func TestTrackingController(t *testing.T) {
+	s := &Ship{Range:1000, SensorNoise:0.001}
+	target := &Ship{Position:Vector{X:500}, Velocity:Vector{Y:5}}
+	w := &World{Ships:[]*Ship{s, target}, Rand:rand.New(rand.NewSource(3))}
+	tr := &Tracker{MeasurementNoise:0.5, ProcessNoise:0.1, Gate:5, Confirm:3,
+		Drop:3}
+	s.SetController(&TrackingController{Ship:s, World:w, Tracker:tr,
+		Maneuver:func(s, t *Ship) { s.Circle(&t.Position, 1) }})
+	w.Step(0.1)
+	if !s.Acceleration.IsZero() {
+		t.Error("Ship steered before the track was confirmed.")
+	}
+	for i := 0; i < 10; i++ { w.Step(0.1) }
+	if s.Acceleration.X < 0.99 {
+		t.Errorf("Expected to steer toward the target, got %v", s.Acceleration)
+	}
+}
@@ -0,0 +1,225 @@
+// NPC traders: merchants who plan buy/sell runs and fly them.
+
+package lib
+
+import (
+	"sort"
+)
+
+// A trade run: buy at Stops[Buy], sell at the last stop.
+type TradePlan struct {
+	Commodity *Commodity
+	Quantity float64
+	Stops []*Star
+	Buy int
+	// Expected profit after fuel and risk.
+	Profit float64
+	// Credits kept back from the purchase for fuel.
+	reserve float64
+	next int
+	bought bool
+}
+
+const (
+	// How close to the port's hull, and at what speed, counts as docked.
+	dockingRange = 10.0
+	dockingSpeed = 1.0
+)
+
+// A merchant captain. Plans come from the faction's Knowledge, so prices
+// may be stale; the trader refreshes them at every market it visits.
+type Trader struct {
+	Ship *Ship
+	Knowledge *Knowledge
+	Lanes *JumpGraph
+	// Systems by star Id.
+	Systems map[int]*System
+	FuelCapacity float64
+	// Credits a route is marked down for each recent sighting of a hostile
+	// ship in a system along it.
+	RiskAversion float64
+	// Seconds a sighting counts toward risk.
+	Memory float64
+	Plan *TradePlan
+	pilot *Autopilot
+}
+
+// Armed ships of other factions are taken as pirates.
+func (t *Trader) hostile(s *Ship) bool {
+	return s.Faction != t.Ship.Faction && len(s.Weapons) > 0
+}
+
+// Return the number of recent hostile sightings along the stops.
+func (t *Trader) risk(stops []*Star, now float64) int {
+	n := 0
+	for _, star := range stops {
+		for _, s := range t.Knowledge.SeenAt(star, now, t.Memory) {
+			if t.hostile(s) { n++ }
+		}
+	}
+	return n
+}
+
+func (t *Trader) system() *System { return t.Ship.Drive.System }
+
+// Fill the tanks from the local market, as far as its stock and our
+// credits allow.
+func (t *Trader) refuel() {
+	m := t.system().Market
+	need := t.FuelCapacity - t.Ship.Fuel
+	if stock := m.Goods[Fuel]; stock == nil {
+		return
+	} else if stock.Quantity < need {
+		need = stock.Quantity
+	}
+	if need = affordable(m, Fuel, need, t.Ship.Credits); need > 1e-6 {
+		m.Refuel(t.Ship, need)
+	}
+}
+
+// Return how much of q units of c the credits buy at m. Buying raises the
+// price, so this may be a little less than the price alone suggests.
+func affordable(m *Market, c *Commodity, q, credits float64) float64 {
+	if price := m.Price(c); price > 0 && q * price > credits {
+		q = credits / price
+	}
+	for q > 1e-6 && q * m.quote(c, q) > credits { q *= 0.9 }
+	return q
+}
+
+// Choose the most profitable run the trader knows of, or nil. Tanks are
+// topped up at every stop, starting here.
+func (t *Trader) plan(now float64) *TradePlan {
+	here := t.system().Star
+	fuelPrice := t.system().Market.Price(Fuel)
+	p := &RoutePlan{Cost: LeastFuel, FuelPerJump: t.Ship.Drive.FuelPerJump,
+		FuelPerParsec: t.Ship.Drive.FuelPerParsec, Capacity: t.FuelCapacity,
+		Fuel: t.FuelCapacity,
+		Refuel: func(s *Star) bool { return t.Systems[s.Id] != nil }}
+	var ids []int
+	for id := range t.Knowledge.Markets { ids = append(ids, id) }
+	sort.Ints(ids)
+	var best *TradePlan
+	for _, a := range ids {
+		from := t.Knowledge.Stars[a]
+		toFrom, err := t.Lanes.Route(here, from, p)
+		if err != nil { continue }
+		buy, _, _ := t.Knowledge.Prices(from, now)
+		for _, b := range ids {
+			if b == a { continue }
+			to := t.Knowledge.Stars[b]
+			run, err := t.Lanes.Route(from, to, p)
+			if err != nil { continue }
+			sell, _, _ := t.Knowledge.Prices(to, now)
+			stops := append(append([]*Star{}, toFrom.Stars...), run.Stars[1:]...)
+			fuel := fuelPrice * (toFrom.Fuel + run.Fuel)
+			overhead := fuel + t.RiskAversion * float64(t.risk(stops, now))
+			for _, c := range Commodities {
+				if buy[c.Name] <= 0 || sell[c.Name] <= buy[c.Name] { continue }
+				q := t.Ship.Hold.Capacity / c.Mass
+				if budget := (t.Ship.Credits - overhead) / buy[c.Name]; budget < q {
+					q = budget
+				}
+				// Here we know what the market will actually charge.
+				if m := t.system().Market; from == here {
+					q = affordable(m, c, q, t.Ship.Credits - overhead)
+				}
+				if q < 1 { continue }
+				profit := q * (sell[c.Name] - buy[c.Name]) - overhead
+				if profit > 0 && (best == nil || profit > best.Profit) {
+					best = &TradePlan{Commodity: c, Quantity: q, Stops: stops,
+						Buy: len(toFrom.Stars) - 1, Profit: profit, reserve: fuel}
+				}
+			}
+		}
+	}
+	return best
+}
+
+// Return the nearest hostile ship the trader can see, if any.
+func (t *Trader) threat() *Ship {
+	var nearest *Ship
+	for _, c := range t.system().World.Scan(t.Ship) {
+		if !t.hostile(c.Ship) { continue }
+		if nearest == nil ||
+			t.Ship.Distance(c.Ship) < t.Ship.Distance(nearest) {
+			nearest = c.Ship
+		}
+	}
+	return nearest
+}
+
+// Fly to the system's port, reporting whether the ship is docked there.
+// In systems without one, ships trade where they are.
+func (t *Trader) dock() bool {
+	port := t.system().Port()
+	if port == nil { return true }
+	if t.pilot == nil || t.pilot.Waypoints[0].Body != port {
+		t.pilot = &Autopilot{Ship: t.Ship,
+			Waypoints: []Waypoint{{Body: port, Standoff: dockingRange}},
+			Tolerance: dockingRange, SpeedTolerance: dockingSpeed}
+	}
+	// Judge afresh each step whether we are there.
+	t.pilot.Leg = 0
+	t.pilot.Redirect()
+	return t.pilot.Done()
+}
+
+// Decide the trader's next move; called every World step. All business
+// with the market is done docked at the port.
+func (t *Trader) Redirect() {
+	s, sys := t.Ship, t.system()
+	now := sys.World.Time
+	t.Knowledge.ReportPrices(sys.Star, now, sys.Market.Prices())
+	t.Knowledge.Observe(sys.Star, sys.World.Scan(s))
+	// Hold station while charging the drive.
+	s.Acceleration = *s.Velocity.Times(-1)
+	threat := t.threat()
+	if threat != nil {
+		// Run, and drop a plan that now looks too dangerous.
+		s.Flee(&threat.Position, s.steeringThrust())
+		if t.Plan != nil && t.Plan.Profit < t.RiskAversion { t.abandon() }
+	}
+	if s.Drive.Destination != nil { return }
+	if t.Plan == nil {
+		if threat != nil || !t.dock() { return }
+		t.refuel()
+		if t.Plan = t.plan(now); t.Plan == nil { return }
+	}
+	p := t.Plan
+	for p.next < len(p.Stops) && p.Stops[p.next] == sys.Star { p.next++ }
+	buying, selling := p.next - 1 == p.Buy && !p.bought, p.next == len(p.Stops)
+	refuelling := false
+	if !selling {
+		c, err := s.Drive.Cost(p.Stops[p.next])
+		refuelling = err == nil && s.Fuel < c
+	}
+	if (buying || selling || refuelling) && (threat != nil || !t.dock()) {
+		return
+	}
+	if buying {
+		m, c := sys.Market, p.Commodity
+		q := affordable(m, c, p.Quantity, s.Credits - p.reserve)
+		if q < 1 || m.Buy(s, c, q) != nil {
+			t.Plan = nil
+			return
+		}
+		p.bought = true
+	}
+	if selling {
+		sys.Market.Sell(s, p.Commodity, s.Hold.Goods[p.Commodity])
+		t.Plan = nil
+		return
+	}
+	if refuelling { t.refuel() }
+	if s.Jump(t.Systems[p.Stops[p.next].Id]) != nil { t.abandon() }
+}
+
+// Give up on the current plan, making the cargo, if any, to be sold here.
+func (t *Trader) abandon() {
+	if !t.Plan.bought {
+		t.Plan = nil
+		return
+	}
+	t.Plan.Stops, t.Plan.next = []*Star{t.system().Star}, 0
+}
@@ -0,0 +1,123 @@
+package lib
+
+import (
+	"testing"
+)
+
+// Three systems a parsec or so apart, with food cheap in the first and dear
+// in the other two.
+func tradeRoute() ([]*System, map[int]*System, *JumpGraph) {
+	var systems []*System
+	var stars []*Star
+	index := make(map[int]*System)
+	for i := 0; i < 3; i++ {
+		star := &Star{Id:100 + i, Name:string('A' + rune(i)), Class:"G2V"}
+		if i == 1 { star.X = 1 }
+		if i == 2 { star.Y = 1 }
+		sys := NewSystem(star)
+		systems, stars = append(systems, sys), append(stars, star)
+		index[star.Id] = sys
+	}
+	food := systems[0].Market.Goods[Food]
+	food.Quantity = 4 * food.Equilibrium
+	for _, sys := range systems[1:] {
+		food = sys.Market.Goods[Food]
+		food.Equilibrium, food.Quantity = 1000, 500
+	}
+	return systems, index, NewJumpGraph(stars, 1.5)
+}
+
+func newTrader(systems []*System, index map[int]*System, lanes *JumpGraph) *Trader {
+	s := &Ship{Credits:1000, Hold:Hold{Capacity:100}, MaxAcceleration:1,
+		Drive:&JumpDrive{Range:1.5, ChargeTime:5, FuelPerParsec:1,
+			System:systems[0]}}
+	t := &Trader{Ship:s, Knowledge:NewKnowledge(), Lanes:lanes,
+		Systems:index, FuelCapacity:4, RiskAversion:500, Memory:1000}
+	for _, sys := range systems {
+		t.Knowledge.ReportPrices(sys.Star, 0, sys.Market.Prices())
+	}
+	s.SetController(t)
+	systems[0].World.Add(s)
+	return t
+}
+
+func TestTraderPlansProfitableRun(t *testing.T) {
+	systems, index, lanes := tradeRoute()
+	trader := newTrader(systems, index, lanes)
+	p := trader.plan(0)
+	if p == nil || p.Commodity != Food || p.Buy != 0 ||
+		p.Stops[len(p.Stops)-1] != systems[1].Star {
+		t.Fatalf("Expected to run food from A to B, got %v", p)
+	}
+	for _, sys := range systems { sys.Step(1) }
+	if len(systems[0].Market.Log) != 0 { t.Fatal("Traded before docking.") }
+	for i := 0; i < 5000 && len(systems[1].Market.Log) == 0; i++ {
+		for _, sys := range systems { sys.Step(1) }
+	}
+	port := systems[1].Port()
+	if d := trader.Ship.Position.Distance(&port.Position) - port.Radius;
+		d > 2 * dockingRange {
+		t.Errorf("Expected to sell docked at %v, %vm off", port.Name, d)
+	}
+	if trader.Ship.Drive.System != systems[1] || trader.Plan != nil {
+		t.Errorf("Expected to finish the run at B, at %v with plan %v",
+			trader.Ship.Drive.System.Star.Name, trader.Plan)
+	}
+	if trader.Ship.Credits <= 1000 || len(trader.Ship.Hold.Goods) != 0 {
+		t.Errorf("Run should turn a profit: %v credits, hold %v",
+			trader.Ship.Credits, trader.Ship.Hold.Goods)
+	}
+	if log := systems[1].Market.Log; len(log) != 1 || log[0].Quantity >= 0 {
+		t.Errorf("Expected a sale at B, got %v", log)
+	}
+}
+
+func TestTraderAvoidsPirates(t *testing.T) {
+	systems, index, lanes := tradeRoute()
+	trader := newTrader(systems, index, lanes)
+	pirate := &Ship{Weapons:[]*Weapon{{Kind:Beam}},
+		Faction:NewFaction("Pirates")}
+	trader.Knowledge.Observe(systems[1].Star, []Contact{{Ship:pirate}})
+	p := trader.plan(0)
+	if p == nil || p.Stops[len(p.Stops)-1] != systems[2].Star {
+		t.Fatalf("Expected to run food past the pirates to C, got %v", p)
+	}
+	pirate.Position.X = 10
+	systems[0].World.Add(pirate)
+	trader.Ship.Range = 100
+	systems[0].Step(1)
+	if trader.Ship.Acceleration.X >= 0 {
+		t.Errorf("Trader should flee the pirate, going %v",
+			trader.Ship.Acceleration)
+	}
+}
+
+func TestTraderShortOfCredits(t *testing.T) {
+	systems, index, lanes := tradeRoute()
+	trader := newTrader(systems, index, lanes)
+	trader.Ship.Credits, trader.Ship.Hold.Capacity = 300, 1e4
+	// Free jumps, so every credit goes on the cargo.
+	trader.Ship.Drive.FuelPerParsec, trader.Ship.Fuel = 0, trader.FuelCapacity
+	for i := 0; i < 500 && len(systems[0].Market.Log) == 0; i++ {
+		systems[0].Step(1)
+	}
+	log := systems[0].Market.Log
+	if len(log) == 0 || log[len(log) - 1].Commodity != Food {
+		t.Fatalf("Expected to buy food with what credits allow, got %v", log)
+	}
+	if trader.Plan == nil || !trader.Plan.bought || trader.Ship.Credits < 0 {
+		t.Errorf("Expected to set off with the food, %v credits left",
+			trader.Ship.Credits)
+	}
+}
+
+func TestTraderRefuelsFromStock(t *testing.T) {
+	systems, index, lanes := tradeRoute()
+	trader := newTrader(systems, index, lanes)
+	m := systems[0].Market
+	m.Goods[Fuel].Quantity = 2.5
+	trader.refuel()
+	if trader.Ship.Fuel != 2.5 || m.Goods[Fuel].Quantity != 0 || len(m.Log) != 1 {
+		t.Errorf("Expected to drain the market's 2.5 fuel, got %v", trader.Ship.Fuel)
+	}
+}
@@ -0,0 +1,119 @@
+// Ship-mounted weapons and the projectiles they fire.
+
+package lib
+
+import (
+	"math"
+)
+
+type WeaponKind int
+
+const (
+	// Fires projectiles which have to be led onto the target.
+	Kinetic WeaponKind = iota
+	// Strikes instantly anywhere within range.
+	Beam
+)
+
+type Weapon struct {
+	Kind WeaponKind
+	// Speed of projectiles relative to the firing ship; unused for beams.
+	MuzzleVelocity float64
+	Range float64
+	// Shots per second.
+	RateOfFire float64
+	Damage float64
+	// Seconds until the weapon can fire again.
+	Cooldown float64
+}
+
+// A lightweight body flying in a straight line until it hits something or
+// its time to live runs out.
+type Projectile struct {
+	Position Vector
+	Velocity Vector
+	Owner *Ship
+	Damage float64
+	TTL float64
+}
+
+func (w *Weapon) Ready() bool { return w.Cooldown <= 0 }
+
+// Return the direction to fire a projectile at the given speed (relative
+// to s) so that it meets t, assuming t holds its current velocity and
+// acceleration, along with the time to impact. The last result is false
+// when the projectile can never catch the target.
+func (s *Ship) FiringSolution(t *Ship, speed float64) (*Vector, float64, bool) {
+	p, v, a := t.Position.Minus(&s.Position), t.Velocity.Minus(&s.Velocity),
+		t.Acceleration
+	// Without acceleration, |p + v*T| = speed*T is a quadratic in T.
+	qa, qb, qc := v.Dot(v) - speed*speed, 2*p.Dot(v), p.Dot(p)
+	var time float64
+	if qa == 0 {
+		if qb >= 0 { return nil, 0, false }
+		time = -qc / qb
+	} else {
+		disc := qb*qb - 4*qa*qc
+		if disc < 0 { return nil, 0, false }
+		t1, t2 := (-qb - math.Sqrt(disc)) / (2*qa), (-qb + math.Sqrt(disc)) / (2*qa)
+		if t1 > t2 { t1, t2 = t2, t1 }
+		if time = t1; time < 0 { time = t2 }
+		if time < 0 { return nil, 0, false }
+	}
+	// Refine for acceleration by iterating on the time of flight.
+	aim := func(time float64) *Vector {
+		r := *p
+		r.AddWithScaleInPlace(v, time)
+		r.AddWithScaleInPlace(&a, 0.5*time*time)
+		return &r
+	}
+	if !a.IsZero() {
+		for i := 0; i < 50; i++ {
+			next := aim(time).Length() / speed
+			if math.Abs(next - time) < 1e-9 * (1 + time) {
+				time = next
+				break
+			}
+			time = next
+		}
+		if r := aim(time).Length(); math.Abs(r - speed*time) > 1e-6 * (1 + r) {
+			return nil, 0, false
+		}
+	}
+	return aim(time).Unit(), time, true
+}
+
+// Fire the weapon from s at target, returning false when it is cooling
+// down or offline, or the target is out of range or cannot be hit. Beams
+// strike at once; the hit is reported by the next Step.
+func (w *World) Fire(s *Ship, weapon *Weapon, target *Ship) bool {
+	if !weapon.Ready() || !s.WeaponsOnline() { return false }
+	switch weapon.Kind {
+	case Beam:
+		if s.Distance(target) - target.Radius > weapon.Range { return false }
+		w.pending = append(w.pending, Event{Kind: Hit, Time: w.Time,
+			Ship: target, Other: s, Damage: weapon.Damage,
+			Impact: *s.Position.Minus(&target.Position)})
+	case Kinetic:
+		dir, time, ok := s.FiringSolution(target, weapon.MuzzleVelocity)
+		if !ok || time * weapon.MuzzleVelocity > weapon.Range { return false }
+		p := &Projectile{Position: s.Position, Velocity: s.Velocity,
+			Owner: s, Damage: weapon.Damage,
+			TTL: weapon.Range / weapon.MuzzleVelocity}
+		p.Velocity.AddWithScaleInPlace(dir, weapon.MuzzleVelocity)
+		w.Projectiles = append(w.Projectiles, p)
+	}
+	if weapon.RateOfFire > 0 { weapon.Cooldown = 1 / weapon.RateOfFire }
+	return true
+}
+
+// Fly the world's projectiles for t seconds and drop the spent ones.
+func (w *World) moveProjectiles(t float64) {
+	live := w.Projectiles[:0]
+	for _, p := range w.Projectiles {
+		p.Position.AddWithScaleInPlace(&p.Velocity, t)
+		if p.TTL -= t; p.TTL > 0 { live = append(live, p) }
+	}
+	for i := len(live); i < len(w.Projectiles); i++ { w.Projectiles[i] = nil }
+	w.Projectiles = live
+}
@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestFiringSolution(t *testing.T) {
+	s := &Ship{}
+	target := &Ship{Position:Vector{X:30}, Velocity:Vector{Y:40}}
+	dir, time, ok := s.FiringSolution(target, 50)
+	// A 3-4-5 triangle: the shot travels 50 m/s along (0.6, 0.8) for 1s.
+	if !ok || !fequal(time, 1) || !dir.Equals(&Vector{0.6, 0.8, 0}) {
+		t.Errorf("Expected to fire along (0.6, 0.8) for 1s, got %v for %vs",
+			dir, time)
+	}
+	if _, _, ok = s.FiringSolution(target, 10); ok {
+		t.Error("Slow projectile should not catch a fast target head on.")
+	}
+}
+
+func TestFiringSolutionAccelerating(t *testing.T) {
+	s := &Ship{Velocity:Vector{Z:3}}
+	target := &Ship{Position:Vector{X:100}, Velocity:Vector{Y:10},
+		Acceleration:Vector{Y:4}}
+	dir, time, ok := s.FiringSolution(target, 200)
+	if !ok {
+		t.Fatal("Expected a firing solution.")
+	}
+	shot := s.Position.Plus(s.Velocity.Plus(dir.Times(200)).Times(time))
+	target.Move(time)
+	if d := shot.Distance(&target.Position); d > 1e-6 {
+		t.Errorf("Shot misses the accelerating target by %v", d)
+	}
+}
+
+func TestKineticHit(t *testing.T) {
+	gun := &Weapon{Kind:Kinetic, MuzzleVelocity:50, Range:100, RateOfFire:2,
+		Damage:5}
+	s := &Ship{Radius:1, Weapons:[]*Weapon{gun}}
+	target := &Ship{Position:Vector{X:30}, Velocity:Vector{Y:40}, Radius:1}
+	w := &World{Ships:[]*Ship{s, target}}
+	if !w.Fire(s, gun, target) {
+		t.Fatal("Weapon failed to fire.")
+	}
+	if w.Fire(s, gun, target) {
+		t.Error("Weapon fired while cooling down.")
+	}
+	var hits []Event
+	for i := 0; i < 20; i++ {
+		for _, e := range w.Step(0.1) {
+			if e.Kind == Hit { hits = append(hits, e) }
+		}
+	}
+	if len(hits) != 1 || hits[0].Ship != target || hits[0].Other != s ||
+		hits[0].Damage != 5 || hits[0].Time < 0.9 || hits[0].Time > 1 {
+		t.Errorf("Expected one hit on the target just before 1s, got %v", hits)
+	}
+	if len(w.Projectiles) != 0 {
+		t.Errorf("Spent projectiles remain in flight: %v", w.Projectiles)
+	}
+	if !gun.Ready() {
+		t.Error("Weapon never cooled down.")
+	}
+}
+
+func TestBeamHit(t *testing.T) {
+	beam := &Weapon{Kind:Beam, Range:10, Damage:3}
+	s := &Ship{Weapons:[]*Weapon{beam}}
+	target := &Ship{Position:Vector{X:11}, Radius:2}
+	w := &World{Ships:[]*Ship{s, target}}
+	if !w.Fire(s, beam, target) {
+		t.Fatal("Beam failed to fire at a target in range.")
+	}
+	events := w.Step(0.1)
+	if len(events) != 1 || events[0].Kind != Hit || events[0].Damage != 3 {
+		t.Errorf("Expected a beam hit, got %v", events)
+	}
+	target.Position.X = 20
+	if w.Fire(s, beam, target) {
+		t.Error("Beam fired at a target out of range.")
+	}
+}
@@ -0,0 +1,84 @@
+package lib
+
+import (
+	"math/rand"
+	"sort"
+)
+
+type BodyKind int
+
+const (
+	Rocky BodyKind = iota
+	Ocean
+	Ice
+	GasGiant
+	Station
+)
+
+// A fixed object such as a planet or station.
+type Body struct {
+	Name string
+	Kind BodyKind
+	Position Vector
+	Radius float64
+	// The faction holding a station, if any.
+	Owner *Faction
+}
+
+// Everything flying around a single star system.
+type World struct {
+	Time float64
+	Ships []*Ship
+	Bodies []*Body
+	Projectiles []*Projectile
+	Missiles []*Missile
+	// Least edge length of the broadphase grids. Collision cells are always
+	// made wide enough for the widest swept hull, and range cells for the
+	// longest sensor reach; zero sizes them to just that.
+	CellSize float64
+	// Source of sensor noise; nil for perfect sensors.
+	Rand *rand.Rand
+	// Events raised between steps, such as beam hits.
+	pending []Event
+}
+
+// Advance the world by dt seconds, returning everything that happened.
+func (w *World) Step(dt float64) []Event {
+	for _, s := range w.Ships {
+		if s.Disabled() {
+			s.Acceleration = Vector{}
+			continue
+		}
+		if s.c != nil { s.c.Redirect() }
+		s.limitThrust()
+	}
+	events := append(w.pending, w.detect(dt)...)
+	events = append(events, w.moveMissiles(dt)...)
+	w.pending = nil
+	sort.Stable(byTime(events))
+	events = w.resolve(events)
+	for _, s := range w.Ships {
+		s.Move(dt)
+		s.regenerate(dt)
+		for _, weapon := range s.Weapons {
+			if weapon.Cooldown > 0 { weapon.Cooldown -= dt }
+		}
+	}
+	w.moveProjectiles(dt)
+	events = append(events, w.chargeDrives(dt)...)
+	w.Time += dt
+	return events
+}
+
+// Bring s into the world.
+func (w *World) Add(s *Ship) {
+	w.Ships = append(w.Ships, s)
+}
+
+// Report whether s is in the world.
+func (w *World) contains(s *Ship) bool {
+	for _, t := range w.Ships {
+		if t == s { return true }
+	}
+	return false
+}